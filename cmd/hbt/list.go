@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+	"github.com/vittolewerissa/hbt/internal/today"
+)
+
+// runList prints today's habits in the configured sort order
+func runList(ctx context.Context, database *db.DB, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	sortFlag := flags.String("sort", "", "sort order: manual, name, streak, due-first or category (default: saved setting)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+	service := today.NewService(database)
+	habits, err := service.GetHabitsForToday(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Habits come back in the saved order; re-sort if asked for another
+	if *sortFlag != "" {
+		mode := model.ParseSortMode(*sortFlag)
+		if string(mode) != *sortFlag {
+			return fmt.Errorf("unknown sort order %q", *sortFlag)
+		}
+		sort.SliceStable(habits, func(i, j int) bool {
+			return model.HabitLess(mode, &habits[i].Habit, &habits[j].Habit, habits[i].IsDue, habits[j].IsDue)
+		})
+	}
+
+	for _, h := range habits {
+		check := "[ ]"
+		if h.CompletedToday {
+			check = "[x]"
+		} else if !h.IsDue {
+			check = "[-]"
+		}
+
+		line := check + " "
+		if h.Emoji != "" {
+			line += h.Emoji + " "
+		}
+		line += h.Name
+		if h.Category != nil {
+			line += "  (" + h.Category.Name + ")"
+		}
+		if h.CurrentStreak > 0 {
+			line += fmt.Sprintf("  %d day streak", h.CurrentStreak)
+		}
+		fmt.Fprintln(os.Stdout, line)
+	}
+	return nil
+}
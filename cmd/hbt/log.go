@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/audit"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+// runLog prints a page of the audit log, newest first
+func runLog(ctx context.Context, database *db.DB, args []string) error {
+	flags := flag.NewFlagSet("log", flag.ContinueOnError)
+	entity := flags.String("entity", "", "only changes to: "+strings.Join(db.Entities, ", "))
+	id := flags.String("id", "", "only changes to the row with this ID (or key, for settings and check-ins)")
+	source := flags.String("source", "", "only changes made from: tui, cli, import or api")
+	action := flags.String("action", "", "only changes of kind: create, update or delete")
+	since := flags.String("since", "", "only changes on or after this date (YYYY-MM-DD)")
+	until := flags.String("until", "", "only changes before this date (YYYY-MM-DD)")
+	page := flags.Int("page", 1, "page to show, 1 being the newest")
+	size := flags.Int("n", 20, "events per page")
+	verbose := flags.Bool("v", false, "print the full row before and after each change")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	f := audit.Filter{Entity: *entity, EntityID: *id, Source: *source, Action: *action}
+	if f.Entity != "" && !slices.Contains(db.Entities, f.Entity) {
+		return fmt.Errorf("unknown entity %q", f.Entity)
+	}
+	if f.Source != "" && !slices.Contains([]string{db.SourceTUI, db.SourceCLI, db.SourceImport, db.SourceAPI}, f.Source) {
+		return fmt.Errorf("unknown source %q", f.Source)
+	}
+	if f.Action != "" && !slices.Contains([]string{db.ActionCreate, db.ActionUpdate, db.ActionDelete}, f.Action) {
+		return fmt.Errorf("unknown action %q", f.Action)
+	}
+	var err error
+	if f.Since, err = parseDay(*since); err != nil {
+		return err
+	}
+	if f.Until, err = parseDay(*until); err != nil {
+		return err
+	}
+
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+	p, err := audit.NewService(database).List(ctx, f, *page, *size)
+	if err != nil {
+		return err
+	}
+	if p.Total == 0 {
+		fmt.Fprintln(os.Stdout, "No changes recorded.")
+		return nil
+	}
+
+	for _, e := range p.Events {
+		fmt.Fprintln(os.Stdout, formatEvent(e, *verbose))
+		if *verbose {
+			if e.Before != "" {
+				fmt.Fprintln(os.Stdout, "    before:", e.Before)
+			}
+			if e.After != "" {
+				fmt.Fprintln(os.Stdout, "    after: ", e.After)
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stdout, "\nPage %d of %d (%d changes)", p.Number, p.Pages, p.Total)
+	if p.Number < p.Pages {
+		fmt.Fprintf(os.Stdout, " · older: hbt log --page %d", p.Number+1)
+	}
+	fmt.Fprintln(os.Stdout)
+	return nil
+}
+
+// parseDay parses a YYYY-MM-DD flag as local midnight; empty means unset
+func parseDay(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return t, fmt.Errorf("invalid date %q, want YYYY-MM-DD", s)
+	}
+	return t, nil
+}
+
+// formatEvent renders an event on one line: when, where from, what, and
+// for updates which fields changed. Unless verbose, where the full rows are
+// printed separately, a created or deleted row is shown inline.
+func formatEvent(e model.Event, verbose bool) string {
+	line := fmt.Sprintf("#%-5d %s  %-6s %-6s %s %s",
+		e.ID, e.OccurredAt.Local().Format("2006-01-02 15:04:05"), e.Source, e.Action, e.Entity, e.EntityID)
+
+	if verbose && e.Action != db.ActionUpdate {
+		return line
+	}
+	switch e.Action {
+	case db.ActionCreate:
+		return line + "  " + e.After
+	case db.ActionDelete:
+		return line + "  " + e.Before
+	}
+
+	changes, err := audit.Diff(e)
+	if err != nil {
+		return line + "  (unreadable payload)"
+	}
+	var fields []string
+	for _, c := range changes {
+		fields = append(fields, fmt.Sprintf("%s: %s → %s", c.Field, c.Before, c.After))
+	}
+	return line + "  " + strings.Join(fields, ", ")
+}
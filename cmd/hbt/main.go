@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vittolewerissa/hbt/internal/app"
+	"github.com/vittolewerissa/hbt/internal/shared/clock"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+)
+
+func main() {
+	// Ctrl-C cancels whatever a subcommand is waiting on
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx, os.Args[1:])
+	stop()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "hbt:", db.Explain(err))
+		os.Exit(1)
+	}
+}
+
+// run opens the database and starts the TUI, or runs a subcommand
+func run(ctx context.Context, args []string) error {
+	now, args, err := parseNow(args)
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Open(db.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if now != nil {
+		database.Clock = now
+	}
+
+	if len(args) > 0 {
+		database.Source = db.SourceCLI
+		switch args[0] {
+		case "list":
+			return runList(ctx, database, args[1:])
+		case "log":
+			return runLog(ctx, database, args[1:])
+		case "reindex":
+			return runReindex(ctx, database, args[1:])
+		default:
+			return fmt.Errorf("unknown command %q", args[0])
+		}
+	}
+
+	p := tea.NewProgram(app.New(database), tea.WithAltScreen())
+	_, err = p.Run()
+	return err
+}
+
+// parseNow takes a leading --now 2026-01-01T08:00 off args, which runs hbt
+// as if it were that time, for reproducing bugs with streaks and week
+// boundaries. It's left out of the usage as it's only for debugging. Point
+// XDG_DATA_HOME at a copy of the database, since changes are saved as made
+// at that time.
+func parseNow(args []string) (clock.Clock, []string, error) {
+	flags := flag.NewFlagSet("hbt", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	now := flags.String("now", "", "")
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	if *now == "" {
+		return nil, flags.Args(), nil
+	}
+	t, err := clock.Parse(*now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return clock.StartingAt(t), flags.Args(), nil
+}
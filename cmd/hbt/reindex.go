@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+)
+
+// runReindex rebuilds the cached streaks and totals from the completions
+func runReindex(ctx context.Context, database *db.DB, args []string) error {
+	flags := flag.NewFlagSet("reindex", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	n, err := database.Reindex(ctx)
+	if err != nil {
+		return err
+	}
+	noun := "habits"
+	if n == 1 {
+		noun = "habit"
+	}
+	fmt.Fprintf(os.Stdout, "Rebuilt streaks for %d %s\n", n, noun)
+	return nil
+}
@@ -1,7 +1,9 @@
 package app
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -9,6 +11,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/vittolewerissa/hbt/internal/category"
 	"github.com/vittolewerissa/hbt/internal/habits"
+	"github.com/vittolewerissa/hbt/internal/history"
 	"github.com/vittolewerissa/hbt/internal/shared/db"
 	"github.com/vittolewerissa/hbt/internal/shared/ui"
 	"github.com/vittolewerissa/hbt/internal/stats"
@@ -28,15 +31,46 @@ const numTabs = 3
 
 var tabNames = []string{"Today", "Habits", "Categories"}
 
+// statsReloadDelay is how long the stats panel waits after a change before
+// reloading, in case more changes follow
+const statsReloadDelay = 300 * time.Millisecond
+
+// statsReloadMsg reloads the stats panel if no later reload was scheduled
+type statsReloadMsg struct {
+	seq int
+}
+
 // Model is the main application model
 type Model struct {
-	db        *db.DB
-	keys      ui.KeyMap
-	help      help.Model
-	activeTab Tab
-	width     int
-	height    int
-	ready     bool
+	db          *db.DB
+	keys        ui.KeyMap
+	keysErr     error
+	help        help.Model
+	activeTab   Tab
+	width       int
+	height      int
+	ready       bool
+	showHelp    bool
+	filterQuery string // habit filter shared by the today and habits tabs
+
+	// Layout chosen with the keys: the stats panel turned off, and the tab
+	// panel's percentage of the width beside it
+	statsHidden bool
+	split       int
+
+	// Undo history and the status message shown in place of the help bar
+	history  history.History
+	undoing  bool // an undo or redo is running
+	toast    string
+	toastErr bool
+	toastSeq int
+
+	statsSeq int // the latest scheduled stats reload
+
+	// Notices changes made by other processes; nil if they can't be watched
+	watcher *db.Watcher
+
+	day string // the date the tabs were loaded for
 
 	// Tab models
 	todayModel      today.Model
@@ -47,25 +81,42 @@ type Model struct {
 
 // New creates a new application model
 func New(database *db.DB) Model {
+	// Fall back to the defaults (and show why) if the user's overrides are invalid
+	keys, keysErr := ui.LoadKeyMap(ui.KeyMapPath())
+
+	// Without a watcher the TUI still works, it just won't notice changes
+	// made elsewhere until a tab reloads
+	watcher, err := database.Watch(context.Background())
+	if err != nil {
+		watcher = nil
+	}
+
 	return Model{
 		db:              database,
-		keys:            ui.DefaultKeyMap,
+		keys:            keys,
+		keysErr:         keysErr,
 		help:            help.New(),
 		activeTab:       TabToday,
-		todayModel:      today.New(database),
-		habitsModel:     habits.New(database),
-		categoriesModel: category.New(database),
-		statsModel:      stats.New(database),
+		split:           defaultSplit,
+		todayModel:      today.New(database, keys),
+		habitsModel:     habits.New(database, keys),
+		categoriesModel: category.New(database, keys),
+		statsModel:      stats.New(database, keys),
+		watcher:         watcher,
+		day:             database.Clock.Now().Format("2006-01-02"),
 	}
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
+		tea.EnableMouseCellMotion,
 		m.todayModel.Init(),
 		m.habitsModel.Init(),
 		m.categoriesModel.Init(),
 		m.statsModel.Init(),
+		m.scheduleWatch(),
+		m.waitForNewDay(),
 	)
 }
 
@@ -75,6 +126,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// While the help overlay is open, any key closes it
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		// Forms only open help on non-text keys so "?" can still be typed
+		if key.Matches(msg, m.keys.Help) && (!m.formFocused() || msg.Type != tea.KeyRunes) {
+			m.showHelp = true
+			return m, nil
+		}
+
+		// While the today filter, a note or the check-in is being typed, let it handle all keys
+		if m.activeTab == TabToday && m.todayModel.Focused() {
+			var cmd tea.Cmd
+			m.todayModel, cmd = m.todayModel.Update(msg)
+			return m, cmd
+		}
+
 		// If habits form is focused, let it handle all keys
 		if m.activeTab == TabHabits && m.habitsModel.Focused() {
 			var cmd tea.Cmd
@@ -89,54 +159,119 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// While the stats panel has focus it takes the list keys, and the
+		// tab gets none of the rest
+		if m.statsModel.Focused() {
+			switch {
+			case key.Matches(msg, m.keys.FocusStats, m.keys.Back):
+				m.statsModel.SetFocused(false)
+				return m, nil
+			case m.statsModel.HandleKey(msg):
+				return m, nil
+			}
+		}
+
 		switch {
 		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
 
 		case key.Matches(msg, m.keys.NextTab):
-			oldTab := m.activeTab
-			m.activeTab = (m.activeTab + 1) % numTabs
-			cmds = append(cmds, m.reloadTabData(oldTab)...)
-			return m, tea.Batch(cmds...)
+			return m.switchTab((m.activeTab + 1) % numTabs)
 
 		case key.Matches(msg, m.keys.PrevTab):
-			oldTab := m.activeTab
-			m.activeTab = (m.activeTab - 1 + numTabs) % numTabs
-			cmds = append(cmds, m.reloadTabData(oldTab)...)
-			return m, tea.Batch(cmds...)
+			return m.switchTab((m.activeTab - 1 + numTabs) % numTabs)
+
+		case key.Matches(msg, m.keys.Undo):
+			return m, m.undo(false)
+
+		case key.Matches(msg, m.keys.Redo):
+			return m, m.undo(true)
+
+		case key.Matches(msg, m.keys.ToggleStats):
+			m.statsHidden = !m.statsHidden
+			m.resizeLists()
+			return m, nil
+
+		case key.Matches(msg, m.keys.StatsView):
+			m.statsModel.NextView()
+			return m, nil
+
+		case key.Matches(msg, m.keys.FocusStats):
+			if l := m.layout(); l.stats == statsBeside || l.stats == statsBelow {
+				m.statsModel.SetFocused(true)
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.ShrinkPanel):
+			m.resizeSplit(-splitStep)
+			return m, nil
+
+		case key.Matches(msg, m.keys.GrowPanel):
+			m.resizeSplit(splitStep)
+			return m, nil
+		}
+		if m.statsModel.Focused() {
+			return m, nil
 		}
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.help.Width = msg.Width
+		m.help.Width = msg.Width - viewLeft - 2 // view padding on both sides
 		m.ready = true
+		m.resizeLists()
+
+	case undoneMsg:
+		return m, m.handleUndone(msg)
+
+	case dayTickMsg:
+		return m, m.handleDayTick()
+
+	case watchTickMsg:
+		if m.watcher == nil {
+			return m, nil
+		}
+		return m, m.checkDB
+
+	case dbCheckedMsg:
+		return m, m.handleDBChecked(msg)
+
+	case statsReloadMsg:
+		if msg.seq == m.statsSeq {
+			return m, m.statsModel.Init()
+		}
+		return m, nil
+
+	case toastExpiredMsg:
+		if msg.seq == m.toastSeq {
+			m.toast = ""
+		}
+		return m, nil
 	}
 
-	// Route messages to active tab
-	switch m.activeTab {
-	case TabToday:
-		var cmd tea.Cmd
-		m.todayModel, cmd = m.todayModel.Update(msg)
-		cmds = append(cmds, cmd)
-	case TabHabits:
-		var cmd tea.Cmd
-		m.habitsModel, cmd = m.habitsModel.Update(msg)
-		cmds = append(cmds, cmd)
-	case TabCategories:
-		var cmd tea.Cmd
-		m.categoriesModel, cmd = m.categoriesModel.Update(msg)
-		cmds = append(cmds, cmd)
+	if c := changeOf(msg); c != nil {
+		cmds = append(cmds, m.recordChange(*c))
 	}
 
+	// Route messages to active tab
+	var cmd tea.Cmd
+	m, cmd = m.updateActiveTab(msg)
+	cmds = append(cmds, cmd)
+
 	// Route today messages regardless of active tab
 	switch msg.(type) {
-	case today.TodayLoadedMsg, today.CompletionToggledMsg:
+	case today.TodayLoadedMsg, today.CompletionToggledMsg, today.NoteSavedMsg, today.CheckInSavedMsg:
 		var cmd tea.Cmd
 		m.todayModel, cmd = m.todayModel.Update(msg)
 		cmds = append(cmds, cmd)
-		// Also reload stats when completion changes
-		cmds = append(cmds, m.statsModel.Init())
+		// Also reload stats when completions or check-ins change
+		switch msg.(type) {
+		case today.CompletionToggledMsg, today.CheckInSavedMsg:
+			cmds = append(cmds, m.reloadStats())
+		}
 	}
 
 	// Route habit messages regardless of active tab
@@ -146,17 +281,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.habitsModel, cmd = m.habitsModel.Update(msg)
 		cmds = append(cmds, cmd)
 		// Also reload stats when habits change
-		cmds = append(cmds, m.statsModel.Init())
+		if _, ok := msg.(habits.HabitsLoadedMsg); !ok {
+			cmds = append(cmds, m.reloadStats())
+		}
+	case habits.HabitRestoredMsg:
+		// Restoring is routed to the habits tab, which is the active one
+		cmds = append(cmds, m.reloadStats())
+	case habits.OrderChangedMsg:
+		var cmd tea.Cmd
+		m.habitsModel, cmd = m.habitsModel.Update(msg)
+		cmds = append(cmds, cmd)
+		// The today list follows the same order
+		cmds = append(cmds, m.todayModel.Init())
 	}
 
 	// Route category messages regardless of active tab
 	switch msg.(type) {
-	case category.CategoriesLoadedMsg, category.CategorySavedMsg, category.CategoryDeletedMsg:
+	case category.CategoriesLoadedMsg, category.CategorySavedMsg, category.CategoryDeletedMsg, category.CategoryMovedMsg:
 		var cmd tea.Cmd
 		m.categoriesModel, cmd = m.categoriesModel.Update(msg)
 		cmds = append(cmds, cmd)
 		// Also reload habits when categories change (in case they reference categories)
 		cmds = append(cmds, m.habitsModel.Init())
+		if _, ok := msg.(category.CategoryDeletedMsg); ok {
+			// Deleting moves habits, which changes the today sections
+			cmds = append(cmds, m.todayModel.Init())
+		}
 	}
 
 	// Route stats messages regardless of active tab
@@ -170,6 +320,148 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// updateActiveTab passes a message to the active tab's model
+func (m Model) updateActiveTab(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch m.activeTab {
+	case TabToday:
+		m.todayModel, cmd = m.todayModel.Update(msg)
+	case TabHabits:
+		m.habitsModel, cmd = m.habitsModel.Update(msg)
+	case TabCategories:
+		m.categoriesModel, cmd = m.categoriesModel.Update(msg)
+	}
+	return m, cmd
+}
+
+// switchTab activates a tab, reloading its data if needed
+func (m Model) switchTab(tab Tab) (Model, tea.Cmd) {
+	oldTab := m.activeTab
+	m.activeTab = tab
+
+	// Carry the habit filter between the today and habits lists
+	switch oldTab {
+	case TabToday:
+		m.filterQuery = m.todayModel.FilterQuery()
+	case TabHabits:
+		m.filterQuery = m.habitsModel.FilterQuery()
+	}
+	switch tab {
+	case TabToday:
+		m.todayModel.SetFilter(m.filterQuery)
+	case TabHabits:
+		m.habitsModel.SetFilter(m.filterQuery)
+	}
+	return m, tea.Batch(m.reloadTabData(oldTab)...)
+}
+
+// handleMouse hit-tests clicks and wheel events against the current layout
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || !m.ready {
+		return m, nil
+	}
+
+	// Any click dismisses the help overlay
+	if m.showHelp {
+		if msg.Button == tea.MouseButtonLeft {
+			m.showHelp = false
+		}
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		scroll := ui.ScrollMsg{Delta: 1}
+		if msg.Button == tea.MouseButtonWheelUp {
+			scroll.Delta = -1
+		}
+		if m.activeModal() == "" && m.statsPanelAt(msg.X, msg.Y) {
+			m.statsModel, _ = m.statsModel.Update(scroll)
+			return m, nil
+		}
+		return m.updateActiveTab(scroll)
+	case tea.MouseButtonLeft:
+	default:
+		return m, nil
+	}
+
+	// Modals capture every click, including ones outside the box
+	if modal := m.activeModal(); modal != "" {
+		layer := ui.Centered(m.renderBaseView(false), modal)
+		return m.updateActiveTab(ui.ModalClickMsg{Row: msg.Y - layer.Row, Col: msg.X - layer.Col})
+	}
+
+	if tab, ok := m.tabAt(msg.X, msg.Y); ok {
+		if tab == m.activeTab {
+			return m, nil
+		}
+		return m.switchTab(tab)
+	}
+
+	// Only the left column (tab content) is clickable; the stats panel is read-only
+	l := m.layout()
+	top := viewTop + lipgloss.Height(m.renderTabBar(l.leftWidth)) + panelContentRow
+	left := viewLeft + panelContentCol
+	right := viewLeft + l.leftWidth - panelContentCol
+	bottom := top + l.contentHeight - panelContentRow - 1
+	if msg.X < left || msg.X >= right || msg.Y < top || msg.Y >= bottom {
+		return m, nil
+	}
+	return m.updateActiveTab(ui.ClickMsg{Row: msg.Y - top, Col: msg.X - left})
+}
+
+// statsPanelAt reports whether the stats panel is drawn at the given screen
+// position
+func (m Model) statsPanelAt(x, y int) bool {
+	l := m.layout()
+	left, top := viewLeft, viewTop
+	switch l.stats {
+	case statsBeside:
+		left += l.leftWidth + 1 // +1 for gap
+	case statsBelow:
+		top += lipgloss.Height(m.renderTabBar(l.leftWidth)) + lipgloss.Height(ui.TitledPanel("", "", l.leftWidth, l.contentHeight))
+	default:
+		return false
+	}
+	return x >= left && x < left+l.statsWidth && y >= top && y < top+l.statsHeight
+}
+
+// tabAt returns the tab whose name is drawn at the given screen position
+func (m Model) tabAt(x, y int) (Tab, bool) {
+	var tabs []string
+	for i, name := range tabNames {
+		if Tab(i) == m.activeTab {
+			tabs = append(tabs, ui.ActiveTab.Render(name))
+		} else {
+			tabs = append(tabs, ui.InactiveTab.Render(name))
+		}
+	}
+	height := lipgloss.Height(lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	if y < viewTop || y >= viewTop+height {
+		return 0, false
+	}
+
+	start := viewLeft
+	for i, tab := range tabs {
+		end := start + lipgloss.Width(tab)
+		if x >= start && x < end {
+			return Tab(i), true
+		}
+		start = end
+	}
+	return 0, false
+}
+
+// reloadStats reloads the stats panel shortly after data changes, so a
+// burst of changes such as several quick toggles reloads it only once
+func (m *Model) reloadStats() tea.Cmd {
+	m.statsSeq++
+	seq := m.statsSeq
+	return tea.Tick(statsReloadDelay, func(time.Time) tea.Msg {
+		return statsReloadMsg{seq: seq}
+	})
+}
+
 // reloadTabData returns commands to reload data when switching tabs
 func (m Model) reloadTabData(oldTab Tab) []tea.Cmd {
 	var cmds []tea.Cmd
@@ -185,25 +477,59 @@ func (m Model) View() string {
 		return "Loading..."
 	}
 
-	// Check if there's a modal to overlay
+	// Render the modals with transparent overlay showing background
+	if modals := m.modals(); len(modals) > 0 {
+		return m.renderWithModals(modals)
+	}
+
+	return m.renderBaseView(false)
+}
+
+// modals returns the boxes to overlay on the view, bottom first
+func (m Model) modals() []string {
+	var modals []string
+	if modal := m.activeModal(); modal != "" {
+		modals = append(modals, modal)
+	}
+	// Help overlay sits above everything else
+	if m.showHelp {
+		modals = append(modals, m.renderHelpOverlay())
+	}
+	return modals
+}
+
+// activeModal returns the active tab's modal box, if any
+func (m Model) activeModal() string {
 	if m.activeTab == TabCategories && m.categoriesModel.HasModal() {
-		// Render the modal with transparent overlay showing background
-		return m.renderWithModal()
+		return m.categoriesModel.RenderModalContent()
 	}
 	if m.activeTab == TabHabits && m.habitsModel.HasModal() {
-		// Render the modal with transparent overlay showing background
-		return m.renderWithModal()
+		return m.habitsModel.RenderModalContent()
 	}
+	return ""
+}
 
+// renderBaseView renders the tabs, panels and help bar, optionally dimmed
+// to sit behind a modal
+func (m Model) renderBaseView(dimmed bool) string {
 	// Render main content with stats panel (includes tab bar in left column)
 	content := m.renderMainContent()
 
 	// Render help bar
-	helpView := m.help.View(m.keys)
+	helpView := m.renderHelpBar()
+
+	if dimmed {
+		// Dim the base content by applying muted colors
+		dimStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#4B5563")) // Darker gray
+
+		content = dimStyle.Render(content)
+		helpView = dimStyle.Render(helpView)
+	}
 
 	// Combine everything with explicit top padding via newlines
-	baseView := "\n\n" + lipgloss.NewStyle().
-		PaddingLeft(2).
+	return strings.Repeat("\n", viewTop) + lipgloss.NewStyle().
+		PaddingLeft(viewLeft).
 		PaddingRight(2).
 		PaddingBottom(1).
 		Render(
@@ -213,174 +539,75 @@ func (m Model) View() string {
 				helpView,
 			),
 		)
-
-	return baseView
 }
 
-func (m Model) renderWithModal() string {
-	// Render the base content (dimmed)
-	content := m.renderMainContent()
-	helpView := m.help.View(m.keys)
-
-	// Dim the base content by applying muted colors
-	dimStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#4B5563")) // Darker gray
-
-	dimmedContent := dimStyle.Render(content)
-	dimmedHelp := dimStyle.Render(helpView)
-
-	baseView := "\n\n" + lipgloss.NewStyle().
-		PaddingLeft(2).
-		PaddingRight(2).
-		PaddingBottom(1).
-		Render(
-			lipgloss.JoinVertical(
-				lipgloss.Left,
-				dimmedContent,
-				dimmedHelp,
-			),
-		)
-
-	// Get the modal content based on active tab (just the box, no placement)
-	var modalContent string
-	if m.activeTab == TabCategories {
-		modalContent = m.categoriesModel.RenderModalContent()
-	} else if m.activeTab == TabHabits {
-		modalContent = m.habitsModel.RenderModalContent()
+// renderWithModals draws the modals over the dimmed view, each centered
+// and casting a shadow on what's beneath it
+func (m Model) renderWithModals(modals []string) string {
+	base := m.renderBaseView(true)
+	layers := make([]ui.Layer, len(modals))
+	for i, modal := range modals {
+		layers[i] = ui.Centered(base, modal)
+		layers[i].Shadow = true
 	}
-
-	// Overlay the modal box onto the base view
-	return m.overlayModalOnBase(baseView, modalContent)
+	return ui.Composite(base, layers...)
 }
 
-func (m Model) overlayModalOnBase(base, modal string) string {
-	baseLines := strings.Split(base, "\n")
-	modalLines := strings.Split(modal, "\n")
-
-	// Calculate dimensions
-	baseHeight := len(baseLines)
-	modalHeight := len(modalLines)
-
-	// Find the widest base line (for horizontal centering calculation)
-	var baseWidth int
-	for _, line := range baseLines {
-		w := lipgloss.Width(line)
-		if w > baseWidth {
-			baseWidth = w
-		}
-	}
-
-	// Find modal width
-	var modalWidth int
-	for _, line := range modalLines {
-		w := lipgloss.Width(line)
-		if w > modalWidth {
-			modalWidth = w
-		}
+// formFocused returns true if the active tab is capturing keys for a form
+func (m Model) formFocused() bool {
+	switch m.activeTab {
+	case TabToday:
+		return m.todayModel.Focused()
+	case TabHabits:
+		return m.habitsModel.Focused()
+	case TabCategories:
+		return m.categoriesModel.Focused()
 	}
+	return false
+}
 
-	// Calculate centered position
-	startRow := (baseHeight - modalHeight) / 2
-	if startRow < 0 {
-		startRow = 0
-	}
-	startCol := (baseWidth - modalWidth) / 2
-	if startCol < 0 {
-		startCol = 0
+// renderHelpOverlay renders the bindings for the active tab and mode
+func (m Model) renderHelpOverlay() string {
+	var sections []ui.HelpSection
+	switch {
+	case m.statsModel.Focused():
+		sections = m.statsModel.HelpSections()
+	case m.activeTab == TabToday:
+		sections = m.todayModel.HelpSections()
+	case m.activeTab == TabHabits:
+		sections = m.habitsModel.HelpSections()
+	case m.activeTab == TabCategories:
+		sections = m.categoriesModel.HelpSections()
 	}
 
-	// Start with a copy of base
-	result := make([]string, baseHeight)
-	copy(result, baseLines)
-
-	// Overlay modal lines onto result
-	for i, modalLine := range modalLines {
-		targetRow := startRow + i
-		if targetRow >= 0 && targetRow < baseHeight {
-			baseLine := result[targetRow]
-
-			// We need to insert modalLine at startCol visible position in baseLine
-			// This requires ANSI-aware string manipulation
-
-			// Get visible width of base line
-			baseLineWidth := lipgloss.Width(baseLine)
-
-			// Calculate how much of the base line to keep before and after modal
-			// We'll split the base line into: [left part] [modal] [right part]
-
-			// Build the new line by concatenating visible character ranges
-			var newLine string
-
-			// Add left part (0 to startCol visible chars)
-			if startCol > 0 {
-				newLine += m.getVisibleSubstring(baseLine, 0, startCol)
-			}
-
-			// Add modal content
-			newLine += modalLine
-
-			// Add right part (startCol+modalWidth to end)
-			rightStart := startCol + lipgloss.Width(modalLine)
-			if rightStart < baseLineWidth {
-				newLine += m.getVisibleSubstring(baseLine, rightStart, baseLineWidth)
-			}
-
-			result[targetRow] = newLine
-		}
+	// App-wide keys only apply when a form isn't capturing input
+	if !m.formFocused() {
+		sections = append(sections, ui.HelpSection{
+			Title:    "General",
+			Bindings: []key.Binding{m.keys.NextTab, m.keys.PrevTab, m.keys.Undo, m.keys.Redo, m.keys.ToggleStats, m.keys.StatsView, m.keys.FocusStats, m.keys.ShrinkPanel, m.keys.GrowPanel, m.keys.Help, m.keys.Quit},
+		})
 	}
 
-	return strings.Join(result, "\n")
+	return ui.HelpOverlay("Keyboard Shortcuts", sections)
 }
 
-// getVisibleSubstring extracts a substring based on visible character positions (ANSI-aware)
-// from position start (inclusive) to end (exclusive) in visible character count
-func (m Model) getVisibleSubstring(s string, start, end int) string {
-	if start >= end {
-		return ""
-	}
-
-	// Use lipgloss.Width to measure visible width as we iterate through runes
-	// This is a simplified approach - it strips ANSI codes and works with visible chars
-
-	// For a more robust solution, we'd need ansi-aware truncation
-	// For now, use a simple approach: iterate through the string and track visible position
-
-	var result strings.Builder
-	visiblePos := 0
-	inEscape := false
-
-	for _, r := range s {
-		// Track ANSI escape sequences
-		if r == '\x1b' {
-			inEscape = true
-		}
-
-		if inEscape {
-			result.WriteRune(r)
-			if r == 'm' {
-				inEscape = false
-			}
-			continue
-		}
-
-		// Regular character
-		if visiblePos >= start && visiblePos < end {
-			result.WriteRune(r)
-		}
-		visiblePos++
-
-		// If we've reached the end position, we can keep copying ANSI codes
-		// but stop adding visible characters
-		if visiblePos >= end {
-			// Continue to preserve any remaining ANSI sequences that might reset styles
-			if r == '\x1b' {
-				inEscape = true
-				result.WriteRune(r)
-			}
+// renderHelpBar renders the short help, or the current status message in its
+// place, noting when custom key bindings were rejected
+func (m Model) renderHelpBar() string {
+	helpView := m.help.View(m.keys)
+	if m.toast != "" {
+		color := ui.Success
+		if m.toastErr {
+			color = ui.Danger
 		}
+		helpView = lipgloss.NewStyle().Foreground(color).Render(m.toast)
 	}
-
-	return result.String()
+	if m.keysErr != nil {
+		warning := lipgloss.NewStyle().Foreground(ui.Warning).
+			Render("Using default keys: " + m.keysErr.Error())
+		helpView = lipgloss.JoinVertical(lipgloss.Left, helpView, warning)
+	}
+	return helpView
 }
 
 func (m Model) renderTabBar(width int) string {
@@ -396,41 +623,10 @@ func (m Model) renderTabBar(width int) string {
 	return ui.TabBar.Width(width).Render(tabContent)
 }
 
-func (m Model) renderMainContent() string {
-	// Calculate dimensions
-	contentHeight := m.height - 10 // Account for tab bar, help, padding
-	totalWidth := m.width - 4
-
-	// 60/40 split
-	leftWidth := int(float64(totalWidth) * 0.6)
-	rightWidth := totalWidth - leftWidth - 1 // -1 for gap
-
-	// Render tab bar for left column only
-	tabBar := m.renderTabBar(leftWidth)
-
-	// Render left panel (tab content)
-	var leftContent string
-	var panelTitle string
-	switch m.activeTab {
-	case TabToday:
-		leftContent = m.todayModel.ViewContent()
-		panelTitle = "Today"
-	case TabHabits:
-		leftContent = m.habitsModel.ViewContent()
-		panelTitle = "Habits"
-	case TabCategories:
-		leftContent = m.categoriesModel.ViewContent()
-		panelTitle = "Categories"
-	}
-
-	leftPanel := ui.TitledPanel(panelTitle, leftContent, leftWidth, contentHeight)
-
-	// Combine tab bar and left panel
-	leftColumn := lipgloss.JoinVertical(lipgloss.Left, tabBar, leftPanel)
-
-	// Render right panel (stats) - add extra height for tab bar
-	rightPanel := m.statsModel.RenderPanel(rightWidth, contentHeight+3)
-
-	// Join horizontally
-	return lipgloss.JoinHorizontal(lipgloss.Top, leftColumn, " ", rightPanel)
-}
+// Layout offsets shared by rendering and mouse hit-testing
+const (
+	viewTop         = 2 // blank lines above the tab bar
+	viewLeft        = 2 // left padding of the whole view
+	panelContentRow = 2 // top border and top padding of a titled panel
+	panelContentCol = 3 // side border and left padding of a titled panel
+)
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dayCheckInterval caps how long to wait before checking for a new day, so
+// the rollover isn't missed after a laptop wakes from sleep or the clock
+// changes
+const dayCheckInterval = time.Minute
+
+// dayTickMsg is sent at midnight, or when it's time to check for a new day
+type dayTickMsg struct{}
+
+// waitForNewDay waits until midnight, checking at least once a minute
+func (m Model) waitForNewDay() tea.Cmd {
+	now := m.db.Clock.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	return tea.Tick(min(midnight.Sub(now), dayCheckInterval), func(time.Time) tea.Msg {
+		return dayTickMsg{}
+	})
+}
+
+// handleDayTick starts the new day if the date has changed: per-day state
+// is reset and every tab reloads, since they all work out dates when loading
+func (m *Model) handleDayTick() tea.Cmd {
+	today := m.db.Clock.Now().Format("2006-01-02")
+	if today == m.day {
+		return m.waitForNewDay()
+	}
+	m.day = today
+	m.todayModel.NewDay()
+	return tea.Batch(m.reloadAll(), m.waitForNewDay())
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/vittolewerissa/hbt/internal/shared/db/dbtest"
+)
+
+// turningClock reads a fixed time until it's told to move on a day
+type turningClock struct {
+	start time.Time
+	days  atomic.Int32
+}
+
+func (c *turningClock) Now() time.Time {
+	return c.start.AddDate(0, 0, int(c.days.Load()))
+}
+
+// TestDeleteAcrossNewDay opens the delete dialog, lets the new day reload
+// every tab underneath it, and then confirms
+func TestDeleteAcrossNewDay(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   string // query run while the dialog is open
+		reloaded string // text drawn once the reload has landed
+		deleted  string // text drawn once the confirm has deleted; empty if it shouldn't
+	}{
+		// A habit added above Meditate moves it down the list, but the
+		// dialog still deletes it rather than the habit now under the cursor
+		{"moved", `INSERT INTO habits (name, category_id, position, created_at) SELECT 'Stretch', id, -1, '2026-01-07 12:00:00' FROM categories WHERE name = 'Health'`, "5 habits", "Archived 'Meditate'"},
+		// Meditate is archived elsewhere, so the dialog closes and the
+		// confirm deletes nothing
+		{"gone", `UPDATE habits SET archived_at = '2026-01-07 12:00:00' WHERE name = 'Meditate'`, "Call family", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := seedSnapshotDB(t)
+			clock := &turningClock{start: database.Clock.Now()}
+			database.Clock = clock
+
+			tm := teatest.NewTestModel(t, New(database), teatest.WithInitialTermSize(80, 48))
+			waitForScreen(t, tm, "Meditate", "best")
+			tm.Send(keyPress("tab"))
+			tm.Send(keyPress("d"))
+			waitForScreen(t, tm, "Are you sure you want to delete 'Meditate'?")
+
+			dbtest.Exec(t, database, tt.change)
+			clock.days.Add(1)
+			tm.Send(dayTickMsg{})
+			waitForScreen(t, tm, tt.reloaded)
+			tm.Send(keyPress("y"))
+			if tt.deleted != "" {
+				waitForScreen(t, tm, tt.deleted)
+			}
+
+			if err := tm.Quit(); err != nil {
+				t.Fatal(err)
+			}
+			final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second))
+			if view := ansi.Strip(final.View()); strings.Contains(view, "Are you sure") {
+				t.Errorf("dialog still open:\n%s", view)
+			}
+
+			rows, err := database.QueryContext(t.Context(), `SELECT name FROM habits WHERE archived_at IS NOT NULL`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var archived []string
+			for rows.Next() {
+				var name string
+				if err := rows.Scan(&name); err != nil {
+					t.Fatal(err)
+				}
+				archived = append(archived, name)
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if len(archived) != 1 || archived[0] != "Meditate" {
+				t.Errorf("archived %v, want only Meditate", archived)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vittolewerissa/hbt/internal/category"
+	"github.com/vittolewerissa/hbt/internal/habits"
+	"github.com/vittolewerissa/hbt/internal/history"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/today"
+)
+
+// toastDuration is how long a status message stays in the help bar
+const toastDuration = 4 * time.Second
+
+// undoneMsg is sent when an undo or redo has run
+type undoneMsg struct {
+	change history.Change
+	redo   bool
+	err    error
+}
+
+// toastExpiredMsg clears the toast it was scheduled for
+type toastExpiredMsg struct {
+	seq int
+}
+
+// changeOf returns the reversible change a tab reported in msg, if any
+func changeOf(msg tea.Msg) *history.Change {
+	switch msg := msg.(type) {
+	case today.CompletionToggledMsg:
+		return msg.Change
+	case habits.HabitSavedMsg:
+		return msg.Change
+	case habits.HabitDeletedMsg:
+		return msg.Change
+	case habits.HabitRestoredMsg:
+		return msg.Change
+	case category.CategorySavedMsg:
+		return msg.Change
+	case category.CategoryDeletedMsg:
+		return msg.Change
+	}
+	return nil
+}
+
+// recordChange adds a change reported by a tab to the history and says how
+// to undo it
+func (m *Model) recordChange(c history.Change) tea.Cmd {
+	m.history.Record(c)
+	return m.showToast(fmt.Sprintf("%s — %s to undo", c.Label, m.keys.Undo.Help().Key), false)
+}
+
+// undo reverses the most recent change, or applies the most recently undone
+// one again when redo is set. Only one runs at a time so they stay in order.
+func (m *Model) undo(redo bool) tea.Cmd {
+	if m.undoing {
+		return nil
+	}
+
+	var c history.Change
+	var ok bool
+	if redo {
+		c, ok = m.history.Redo()
+	} else {
+		c, ok = m.history.Undo()
+	}
+	if !ok {
+		if redo {
+			return m.showToast("Nothing to redo", false)
+		}
+		return m.showToast("Nothing to undo", false)
+	}
+
+	m.undoing = true
+	run := c.Undo
+	if redo {
+		run = c.Redo
+	}
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		return undoneMsg{change: c, redo: redo, err: run(ctx)}
+	}
+}
+
+// handleUndone reports an undo or redo and reloads every tab, since a
+// change can touch habits, categories and completions at once
+func (m *Model) handleUndone(msg undoneMsg) tea.Cmd {
+	m.undoing = false
+	if msg.err != nil {
+		m.history.Drop(msg.redo)
+		verb := "undo"
+		if msg.redo {
+			verb = "redo"
+		}
+		return m.showToast(fmt.Sprintf("Couldn't %s %s: %v", verb, msg.change.Label, db.Explain(msg.err)), true)
+	}
+
+	text := fmt.Sprintf("Undid: %s — %s to redo", msg.change.Label, m.keys.Redo.Help().Key)
+	if msg.redo {
+		text = fmt.Sprintf("Redid: %s — %s to undo", msg.change.Label, m.keys.Undo.Help().Key)
+	}
+	return tea.Batch(m.showToast(text, false), m.reloadAll())
+}
+
+// showToast puts a status message in the help bar until it times out or
+// another one replaces it
+func (m *Model) showToast(text string, isErr bool) tea.Cmd {
+	m.toastSeq++
+	m.toast = text
+	m.toastErr = isErr
+	seq := m.toastSeq
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{seq: seq}
+	})
+}
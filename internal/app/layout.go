@@ -0,0 +1,152 @@
+package app
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+	"github.com/vittolewerissa/hbt/internal/stats"
+)
+
+// Breakpoints for arranging the panels, in terminal cells
+const (
+	// Narrower than this, the stats panel no longer fits beside the tab
+	sideBySideMinWidth = 90
+	// A narrow terminal at least this tall has room for the stats panel
+	// under the tab instead
+	stackedMinHeight = 42
+	// At least this wide, the activity heatmap gets a column of its own
+	heatmapMinWidth = 150
+)
+
+// stackedStatsHeight is the height of the stats panel under the tab
+const stackedStatsHeight = 18
+
+// The tab panel's share of the width beside the stats panel, as a
+// percentage, and how far each resize key press moves it
+const (
+	defaultSplit = 60
+	minSplit     = 40
+	maxSplit     = 80
+	splitStep    = 5
+)
+
+// statsPlacement is where the stats panel goes
+type statsPlacement int
+
+const (
+	statsBeside    statsPlacement = iota
+	statsBelow                    // under the tab panel, on tall narrow terminals
+	statsCollapsed                // a one-line summary under the tab panel
+	statsHidden                   // turned off with the toggle key
+)
+
+// screenLayout is how the screen is divided between the panels
+type screenLayout struct {
+	stats         statsPlacement
+	leftWidth     int // tab bar and tab panel
+	contentHeight int // tab panel
+	statsWidth    int
+	statsHeight   int
+	heatmapWidth  int // 0 without a heatmap column
+}
+
+// layout divides the screen between the panels at the current size
+func (m Model) layout() screenLayout {
+	totalWidth := m.width - viewLeft - 2 // view padding on both sides
+	tabBarHeight := lipgloss.Height(m.renderTabBar(totalWidth))
+	l := screenLayout{
+		leftWidth: totalWidth,
+		// Everything but the tab panel: the blank lines above, the tab bar,
+		// and the help bar with the padding under it
+		contentHeight: m.height - viewTop - tabBarHeight - 2,
+	}
+
+	switch {
+	case m.statsHidden:
+		l.stats = statsHidden
+	case m.width >= sideBySideMinWidth:
+		l.stats = statsBeside
+		if m.width >= heatmapMinWidth {
+			l.heatmapWidth = stats.HeatmapWidth
+			totalWidth -= l.heatmapWidth + 1 // +1 for gap
+		}
+		l.leftWidth = totalWidth * m.split / 100
+		l.statsWidth = totalWidth - l.leftWidth - 1 // -1 for gap
+		l.statsHeight = tabBarHeight + l.contentHeight
+	case m.height >= stackedMinHeight:
+		l.stats = statsBelow
+		l.statsWidth = totalWidth
+		l.statsHeight = stackedStatsHeight
+		l.contentHeight -= lipgloss.Height(ui.TitledPanel("", "", l.statsWidth, l.statsHeight))
+	default:
+		l.stats = statsCollapsed
+		l.contentHeight--
+	}
+	return l
+}
+
+// resizeSplit moves the edge between the tab panel and the stats panel by
+// delta percent of the width
+func (m *Model) resizeSplit(delta int) {
+	m.split = min(max(m.split+delta, minSplit), maxSplit)
+	m.resizeLists()
+}
+
+// resizeLists tells each tab how many content rows its panel has so
+// long lists can scroll
+func (m *Model) resizeLists() {
+	l := m.layout()
+	panel := ui.TitledPanel("", "", l.leftWidth, l.contentHeight)
+	rows := lipgloss.Height(panel) - panelContentRow - 1 // -1 for bottom border
+
+	m.todayModel.SetHeight(rows)
+	m.todayModel.SetWidth(l.leftWidth - 10) // borders and padding
+	m.habitsModel.SetHeight(rows)
+	m.categoriesModel.SetHeight(rows)
+
+	// The stats panel only keeps focus while it's drawn in full
+	m.statsModel.SetHeight(l.statsHeight)
+	if l.stats != statsBeside && l.stats != statsBelow {
+		m.statsModel.SetFocused(false)
+	}
+}
+
+func (m Model) renderMainContent() string {
+	l := m.layout()
+
+	// Render tab bar for left column only
+	tabBar := m.renderTabBar(l.leftWidth)
+
+	// Render left panel (tab content)
+	var leftContent string
+	var panelTitle string
+	switch m.activeTab {
+	case TabToday:
+		leftContent = m.todayModel.ViewContent()
+		panelTitle = "Today"
+	case TabHabits:
+		leftContent = m.habitsModel.ViewContent()
+		panelTitle = "Habits"
+	case TabCategories:
+		leftContent = m.categoriesModel.ViewContent()
+		panelTitle = "Categories"
+	}
+
+	leftPanel := ui.TitledPanel(panelTitle, leftContent, l.leftWidth, l.contentHeight)
+
+	// Combine tab bar and left panel
+	leftColumn := lipgloss.JoinVertical(lipgloss.Left, tabBar, leftPanel)
+
+	switch l.stats {
+	case statsBeside:
+		columns := []string{leftColumn, " ", m.statsModel.RenderPanel(l.statsWidth, l.statsHeight)}
+		if l.heatmapWidth > 0 {
+			columns = append(columns, " ", m.statsModel.RenderHeatmap(l.heatmapWidth, l.statsHeight))
+		}
+		return lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+	case statsBelow:
+		return lipgloss.JoinVertical(lipgloss.Left, leftColumn, m.statsModel.RenderPanel(l.statsWidth, l.statsHeight))
+	case statsCollapsed:
+		return lipgloss.JoinVertical(lipgloss.Left, leftColumn, "  "+m.statsModel.RenderSummary())
+	}
+	return leftColumn
+}
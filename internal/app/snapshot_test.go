@@ -0,0 +1,162 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/muesli/termenv"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/db/dbtest"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+func TestMain(m *testing.M) {
+	// Render the same whatever terminal the tests run in, and without the
+	// user's key bindings
+	lipgloss.SetColorProfile(termenv.Ascii)
+	lipgloss.SetHasDarkBackground(true)
+	config, err := os.MkdirTemp("", "hbt-snapshots")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", config)
+	time.Local = time.UTC
+	code := m.Run()
+	os.RemoveAll(config)
+	os.Exit(code)
+}
+
+// snapshotSizes are the terminal sizes every snapshot is taken at
+var snapshotSizes = []struct{ width, height int }{
+	{80, 24},
+	{80, 48},
+	{120, 36},
+	{180, 50},
+}
+
+// snapshots are the scripted sessions whose last screen is compared with
+// the golden files in testdata. Run go test with -update after an intended
+// change to the layout to write them again.
+var snapshots = []struct {
+	name string
+	keys []string // key presses, named as tea.KeyMsg.String() gives them
+	want string   // text the keys bring up, waited for before the screen is taken; empty if nothing loads
+}{
+	{"today", nil, ""},
+	{"today_journal", []string{"N"}, "Journal"},
+	{"habits", []string{"tab"}, "Habits"},
+	{"habit_form", []string{"tab", "a"}, "Habit name"},
+	{"habit_form_edit", []string{"tab", "e"}, "Target/Day"},
+	{"habit_emoji_picker", []string{"tab", "a", "tab", "tab", "enter"}, "Search emojis"},
+	{"habit_emoji_search", []string{"tab", "a", "tab", "tab", "enter", "run", "ctrl+t"}, "tone ✋🏻"},
+	{"habit_delete", []string{"tab", "d"}, "Are you sure"},
+	{"categories", []string{"tab", "tab"}, "Health"},
+	{"category_form", []string{"tab", "tab", "a"}, "Name:"},
+	{"category_form_color", []string{"tab", "tab", "e", "tab", "tab", "l"}, "#FF6B6B"},
+	{"category_emoji_picker", []string{"tab", "tab", "a", "tab", "enter"}, "Search emojis"},
+	{"category_delete", []string{"tab", "tab", "d"}, "Delete"},
+	{"stats_habits", []string{"v"}, ""},
+	{"stats_mood", []string{"v", "v"}, ""},
+	{"stats_focused", []string{"v", "f", "down", "down"}, ""},
+	{"help", []string{"?"}, "Keyboard Shortcuts"},
+	{"stats_hidden", []string{"s"}, ""},
+	{"split_resized", []string{"]", "]"}, ""},
+}
+
+// seedSnapshotDB fills an in-memory database with a few weeks of habits,
+// stopping its clock on Wednesday 7 January 2026
+func seedSnapshotDB(t *testing.T) *db.DB {
+	t.Helper()
+	now := time.Date(2026, 1, 7, 9, 0, 0, 0, time.Local)
+	database := dbtest.Open(t, now)
+	created := now.AddDate(0, 0, -20)
+
+	health := dbtest.Exec(t, database, `INSERT INTO categories (name, color, emoji, position, created_at) VALUES ('Health', '#10B981', '💪', 0, ?)`, db.Timestamp(created))
+	learning := dbtest.Exec(t, database, `INSERT INTO categories (name, color, emoji, position, created_at) VALUES ('Learning', '#3B82F6', '📚', 1, ?)`, db.Timestamp(created))
+
+	habits := []struct {
+		habit model.Habit
+		dates []string
+	}{
+		{model.Habit{Name: "Meditate", Emoji: "🧘", CategoryID: &health, Position: 0}, []string{"2026-01-04", "2026-01-05", "2026-01-06", "2026-01-07"}},
+		{model.Habit{Name: "Run", Emoji: "🏃", CategoryID: &health, FrequencyType: model.FreqTimesPerWeek, FrequencyValue: 3, Position: 1}, []string{"2026-01-02", "2026-01-05"}},
+		{model.Habit{Name: "Read", Emoji: "📖", CategoryID: &learning, TargetPerDay: 2, Position: 2}, []string{"2025-12-30", "2025-12-31", "2026-01-01", "2026-01-07"}},
+		{model.Habit{Name: "Call family", FrequencyType: model.FreqWeekly, Position: 3}, []string{"2026-01-03"}},
+	}
+	for _, h := range habits {
+		h.habit.CreatedAt = created
+		id := dbtest.AddHabit(t, database, h.habit)
+		dbtest.Complete(t, database, id, h.dates...)
+	}
+	dbtest.Exec(t, database, `UPDATE completions SET notes = 'Ten minutes, felt calm' WHERE completed_at = '2026-01-06'`)
+	// Checked in already, so the check-in isn't offered over the list
+	dbtest.Exec(t, database, `INSERT INTO checkins (date, mood, energy, notes, created_at) VALUES ('2026-01-07', 4, 3, '', ?)`, database.Now())
+	dbtest.Exec(t, database, `INSERT INTO settings (key, value) VALUES ('recent_emojis', '🏃:1.9,📖:1,🧘:0.9')`)
+	return database
+}
+
+// keyPress returns the key message named as tea.KeyMsg.String() gives it
+func keyPress(name string) tea.KeyMsg {
+	keys := map[string]tea.KeyType{
+		"tab":       tea.KeyTab,
+		"shift+tab": tea.KeyShiftTab,
+		"enter":     tea.KeyEnter,
+		"esc":       tea.KeyEsc,
+		"up":        tea.KeyUp,
+		"down":      tea.KeyDown,
+		"ctrl+t":    tea.KeyCtrlT,
+	}
+	if k, ok := keys[name]; ok {
+		return tea.KeyMsg{Type: k}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+}
+
+// waitForScreen waits until text is drawn. Only output not yet read by an
+// earlier wait counts, and the renderer only redraws lines that change.
+func waitForScreen(t *testing.T, tm *teatest.TestModel, text ...string) {
+	t.Helper()
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		screen := ansi.Strip(string(out))
+		for _, s := range text {
+			if !strings.Contains(screen, s) {
+				return false
+			}
+		}
+		return true
+	}, teatest.WithDuration(5*time.Second))
+}
+
+func TestSnapshots(t *testing.T) {
+	for _, s := range snapshots {
+		for _, size := range snapshotSizes {
+			t.Run(fmt.Sprintf("%s/%dx%d", s.name, size.width, size.height), func(t *testing.T) {
+				tm := teatest.NewTestModel(t, New(seedSnapshotDB(t)), teatest.WithInitialTermSize(size.width, size.height))
+
+				// Every tab loads at startup; wait for the one shown and
+				// the stats, whether as a panel or summed up on one line
+				waitForScreen(t, tm, "Meditate", "best")
+				for _, k := range s.keys {
+					tm.Send(keyPress(k))
+				}
+				if s.want != "" {
+					waitForScreen(t, tm, s.want)
+				}
+
+				if err := tm.Quit(); err != nil {
+					t.Fatal(err)
+				}
+				final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second))
+				golden.RequireEqual(t, []byte(ansi.Strip(final.View())))
+			})
+		}
+	}
+}
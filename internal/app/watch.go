@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+)
+
+// watchInterval is how often the database is checked for changes made
+// outside this TUI
+const watchInterval = 500 * time.Millisecond
+
+// watchTickMsg is sent when it's time to check the database again
+type watchTickMsg struct{}
+
+// dbCheckedMsg reports whether the database changed since the last check
+type dbCheckedMsg struct {
+	changed bool
+	err     error
+}
+
+// scheduleWatch waits for the next database check
+func (m Model) scheduleWatch() tea.Cmd {
+	if m.watcher == nil {
+		return nil
+	}
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		return watchTickMsg{}
+	})
+}
+
+// checkDB checks whether the database changed. Only one check runs at a
+// time, as the next is scheduled once this one is handled.
+func (m Model) checkDB() tea.Msg {
+	ctx, cancel := db.WithTimeout(context.Background())
+	defer cancel()
+	changed, err := m.watcher.Changed(ctx)
+	return dbCheckedMsg{changed: changed, err: err}
+}
+
+// handleDBChecked reloads every tab when the database changed, then waits
+// for the next check. Watching stops if the database can't be checked.
+func (m *Model) handleDBChecked(msg dbCheckedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.watcher.Close()
+		m.watcher = nil
+		return m.showToast(fmt.Sprintf("Stopped watching for outside changes: %v", db.Explain(msg.err)), true)
+	}
+	if !msg.changed {
+		return m.scheduleWatch()
+	}
+	return tea.Batch(m.reloadAll(), m.scheduleWatch())
+}
+
+// reloadAll reloads the data of every tab
+func (m Model) reloadAll() tea.Cmd {
+	return tea.Batch(
+		m.todayModel.Init(),
+		m.habitsModel.Reload(),
+		m.categoriesModel.Init(),
+		m.statsModel.Init(),
+	)
+}
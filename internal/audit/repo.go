@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+// Repository reads the events table
+type Repository struct {
+	db *db.DB
+}
+
+// NewRepository creates a new audit repository
+func NewRepository(database *db.DB) *Repository {
+	return &Repository{db: database}
+}
+
+// Filter narrows the events listed. Zero fields match everything.
+type Filter struct {
+	Entity   string
+	EntityID string
+	Source   string
+	Action   string
+	Since    time.Time // on or after
+	Until    time.Time // before
+}
+
+// where builds the WHERE clause and arguments for a filter
+func (f Filter) where() (string, []any) {
+	var conds []string
+	var args []any
+	add := func(cond string, arg any) {
+		conds = append(conds, cond)
+		args = append(args, arg)
+	}
+
+	if f.Entity != "" {
+		add("entity = ?", f.Entity)
+	}
+	if f.EntityID != "" {
+		add("entity_id = ?", f.EntityID)
+	}
+	if f.Source != "" {
+		add("source = ?", f.Source)
+	}
+	if f.Action != "" {
+		add("action = ?", f.Action)
+	}
+	if !f.Since.IsZero() {
+		add("occurred_at >= ?", f.Since.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if !f.Until.IsZero() {
+		add("occurred_at < ?", f.Until.UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// Count returns how many events match a filter
+func (r *Repository) Count(ctx context.Context, f Filter) (int, error) {
+	where, args := f.where()
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM events `+where, args...).Scan(&count)
+	return count, err
+}
+
+// List returns up to limit events matching a filter, newest first,
+// skipping the first offset
+func (r *Repository) List(ctx context.Context, f Filter, limit, offset int) ([]model.Event, error) {
+	where, args := f.where()
+	query := `
+		SELECT id, occurred_at, source, entity, entity_id, action, before, after
+		FROM events ` + where + `
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.Event
+	for rows.Next() {
+		var e model.Event
+		var before, after sql.NullString
+		if err := rows.Scan(&e.ID, &e.OccurredAt, &e.Source, &e.Entity, &e.EntityID, &e.Action, &before, &after); err != nil {
+			return nil, err
+		}
+		e.Before, e.After = before.String, after.String
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+// Service handles reading the audit log
+type Service struct {
+	repo *Repository
+}
+
+// NewService creates a new audit service
+func NewService(database *db.DB) *Service {
+	return &Service{repo: NewRepository(database)}
+}
+
+// Page is one page of events, newest first
+type Page struct {
+	Events []model.Event
+	Number int // 1-based
+	Pages  int
+	Total  int
+}
+
+// List returns page number n of the events matching a filter, size events
+// to a page
+func (s *Service) List(ctx context.Context, f Filter, n, size int) (*Page, error) {
+	if n < 1 || size < 1 {
+		return nil, fmt.Errorf("page and page size must be at least 1")
+	}
+	total, err := s.repo.Count(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.repo.List(ctx, f, size, (n-1)*size)
+	if err != nil {
+		return nil, err
+	}
+	return &Page{
+		Events: events,
+		Number: n,
+		Pages:  (total + size - 1) / size,
+		Total:  total,
+	}, nil
+}
+
+// FieldChange is one field that differs between an event's before and after
+type FieldChange struct {
+	Field  string
+	Before string // JSON value, "null" when unset
+	After  string
+}
+
+// Diff returns the fields an update changed, in name order
+func Diff(e model.Event) ([]FieldChange, error) {
+	var before, after map[string]json.RawMessage
+	if err := unmarshalRow(e.Before, &before); err != nil {
+		return nil, err
+	}
+	if err := unmarshalRow(e.After, &after); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]bool)
+	for f := range before {
+		fields[f] = true
+	}
+	for f := range after {
+		fields[f] = true
+	}
+
+	var changes []FieldChange
+	for f := range fields {
+		b, a := rawValue(before[f]), rawValue(after[f])
+		if b != a {
+			changes = append(changes, FieldChange{Field: f, Before: b, After: a})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+func unmarshalRow(s string, row *map[string]json.RawMessage) error {
+	if s == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(s), row)
+}
+
+func rawValue(v json.RawMessage) string {
+	if v == nil {
+		return "null"
+	}
+	return string(v)
+}
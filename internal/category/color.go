@@ -0,0 +1,77 @@
+package category
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+)
+
+// colorField is the category form's color: a palette to pick from with
+// the arrow keys and a hex input for any other color. Both show the same
+// color; picking from the palette fills in the hex.
+type colorField struct {
+	hex textinput.Model
+}
+
+// newColorField creates a color field showing color
+func newColorField(color string) colorField {
+	hex := textinput.New()
+	hex.Placeholder = "#RRGGBB"
+	hex.CharLimit = 7
+	hex.Width = 10
+	hex.SetValue(color)
+	return colorField{hex: hex}
+}
+
+// value returns the color in "#RRGGBB" form, or false while the hex isn't
+// a color
+func (c colorField) value() (string, bool) {
+	return model.NormalizeColor(c.hex.Value())
+}
+
+// paletteIndex returns the palette position of the color, or -1 for a
+// color that isn't in the palette
+func (c colorField) paletteIndex() int {
+	color, _ := c.value()
+	return slices.Index(model.DefaultColors, color)
+}
+
+// step picks the palette color delta places along, wrapping around. From
+// a color that isn't in the palette it starts at either end.
+func (c *colorField) step(delta int) {
+	n := len(model.DefaultColors)
+	i := c.paletteIndex()
+	if i < 0 {
+		i = 0
+		if delta < 0 {
+			i = n - 1
+		}
+	} else {
+		i = ((i+delta)%n + n) % n
+	}
+	c.hex.SetValue(model.DefaultColors[i])
+	c.hex.CursorEnd()
+}
+
+// paletteView renders the palette swatches, bracketing the chosen one
+func (c colorField) paletteView(focused bool) string {
+	chosen := c.paletteIndex()
+	var s strings.Builder
+	for i, color := range model.DefaultColors {
+		left, right := " ", " "
+		if i == chosen {
+			left, right = "[", "]"
+			if focused {
+				left, right = ui.SelectedItem.Render(left), ui.SelectedItem.Render(right)
+			}
+		}
+		s.WriteString(left + ui.ColorSwatch(color) + right)
+	}
+	if chosen < 0 {
+		s.WriteString(ui.MutedText.Render(" custom"))
+	}
+	return s.String()
+}
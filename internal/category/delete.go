@@ -0,0 +1,298 @@
+package category
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/history"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+)
+
+// deleteOption is a choice in the delete category dialog
+type deleteOption int
+
+const (
+	deleteMove   deleteOption = iota // move the habits to another category
+	deleteMerge                      // merge the category into another one
+	deleteAnyway                     // leave the habits uncategorized
+)
+
+// CategoryUsageMsg is sent with the number of habits in a category about to
+// be deleted
+type CategoryUsageMsg struct {
+	ID       int64
+	Active   int
+	Archived int
+	Err      error
+}
+
+func (m Model) countHabits(id int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		active, archived, err := m.service.CountHabits(ctx, id)
+		return CategoryUsageMsg{ID: id, Active: active, Archived: archived, Err: err}
+	}
+}
+
+func (m Model) moveAndDelete(c, target model.Category) tea.Cmd {
+	return m.removeCategory(fmt.Sprintf("Deleted '%s'", c.Name), func(ctx context.Context) (*DeletedCategory, error) {
+		return m.service.MoveAndDelete(ctx, c.ID, target.ID)
+	})
+}
+
+func (m Model) mergeCategory(c, target model.Category) tea.Cmd {
+	return m.removeCategory(fmt.Sprintf("Merged '%s' into '%s'", c.Name, target.Name), func(ctx context.Context) (*DeletedCategory, error) {
+		return m.service.Merge(ctx, c.ID, target.ID)
+	})
+}
+
+// removeCategory runs a delete, move or merge, recording how to undo it.
+// Redoing runs remove again, which takes a fresh snapshot for the next undo.
+func (m Model) removeCategory(label string, remove func(ctx context.Context) (*DeletedCategory, error)) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		deleted, err := remove(ctx)
+		if err != nil {
+			return CategoryDeletedMsg{Err: err}
+		}
+		return CategoryDeletedMsg{Change: &history.Change{
+			Label: label,
+			Undo:  func(ctx context.Context) error { return m.service.Restore(ctx, deleted) },
+			Redo: func(ctx context.Context) error {
+				var err error
+				deleted, err = remove(ctx)
+				return err
+			},
+		}}
+	}
+}
+
+// handleUsage opens the delete dialog once the selected category's habits
+// have been counted
+func (m Model) handleUsage(msg CategoryUsageMsg) Model {
+	if msg.Err != nil {
+		m.err = msg.Err
+		return m
+	}
+	if len(m.categories) == 0 || m.categories[m.list.Cursor].ID != msg.ID {
+		return m
+	}
+	m.deleting = m.categories[m.list.Cursor]
+	m.usage = msg
+	m.deleteCursor = 0
+	m.mode = modeConfirmDelete
+	return m
+}
+
+// syncDeleteDialog keeps the delete dialog in step with a reload: it closes
+// if its category was deleted elsewhere, and otherwise keeps the cursors on
+// the options and targets that are left
+func (m *Model) syncDeleteDialog() {
+	if m.mode != modeConfirmDelete && m.mode != modePickTarget {
+		return
+	}
+	if !slices.ContainsFunc(m.categories, func(c model.Category) bool { return c.ID == m.deleting.ID }) {
+		m.mode = modeList
+		return
+	}
+	m.deleteCursor = min(m.deleteCursor, len(m.deleteOptions())-1)
+	m.targetList.Clamp(len(m.deleteTargets()))
+	m.syncTargetScroll()
+}
+
+// deleteOptions returns the choices offered for the category being deleted.
+// Moving needs habits to move and somewhere to move them; merging needs
+// another category.
+func (m Model) deleteOptions() []deleteOption {
+	var options []deleteOption
+	others := len(m.categories) > 1
+	if others && m.usage.Active+m.usage.Archived > 0 {
+		options = append(options, deleteMove)
+	}
+	if others {
+		options = append(options, deleteMerge)
+	}
+	return append(options, deleteAnyway)
+}
+
+// deleteTargets returns the categories the one being deleted can be moved or
+// merged into
+func (m Model) deleteTargets() []model.Category {
+	targets := make([]model.Category, 0, len(m.categories))
+	for _, c := range m.categories {
+		if c.ID != m.deleting.ID {
+			targets = append(targets, c)
+		}
+	}
+	return targets
+}
+
+// handleDeleteKey handles keys in the delete dialog
+func (m Model) handleDeleteKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	options := m.deleteOptions()
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.deleteCursor > 0 {
+			m.deleteCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.deleteCursor < len(options)-1 {
+			m.deleteCursor++
+		}
+	case key.Matches(msg, m.keys.Select):
+		switch options[m.deleteCursor] {
+		case deleteMove, deleteMerge:
+			m.deleteAction = options[m.deleteCursor]
+			m.targetList = ui.ScrollList{}
+			m.mode = modePickTarget
+			m.syncTargetScroll()
+		case deleteAnyway:
+			m.mode = modeList
+			return m, m.deleteCategory(m.deleting)
+		}
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Back):
+		m.mode = modeList
+	}
+	return m, nil
+}
+
+// handleTargetKey handles keys while picking the category to move or merge into
+func (m Model) handleTargetKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	targets := m.deleteTargets()
+	if m.targetList.HandleKey(msg, m.keys, len(targets)) {
+		m.syncTargetScroll()
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Select):
+		if len(targets) == 0 {
+			return m, nil
+		}
+		target := targets[m.targetList.Cursor]
+		m.mode = modeList
+		if m.deleteAction == deleteMerge {
+			return m, m.mergeCategory(m.deleting, target)
+		}
+		return m, m.moveAndDelete(m.deleting, target)
+	case key.Matches(msg, m.keys.Back):
+		m.mode = modeConfirmDelete
+	}
+	return m, nil
+}
+
+// syncTargetScroll sizes the target list to the panel, leaving room for the
+// question and key hints
+func (m *Model) syncTargetScroll() {
+	m.targetList.Height = 0
+	if m.panelHeight > 0 {
+		m.targetList.Height = max(1, m.panelHeight-4)
+	}
+	m.targetList.Follow(m.targetList.Cursor, m.targetList.Cursor, len(m.categories)-1)
+}
+
+// habitCount describes the habits in the category, e.g. "3 habits (1 archived)"
+func (m Model) habitCount() string {
+	total := m.usage.Active + m.usage.Archived
+	s := fmt.Sprintf("%d habits", total)
+	if total == 1 {
+		s = "1 habit"
+	}
+	if m.usage.Archived > 0 {
+		s += fmt.Sprintf(" (%d archived)", m.usage.Archived)
+	}
+	return s
+}
+
+func (m Model) renderConfirmDeleteContent() string {
+	cat := m.deleting
+	total := m.usage.Active + m.usage.Archived
+
+	question := fmt.Sprintf("Delete '%s'?", cat.Name)
+	usage := ui.MutedText.Render("No habits use this category.")
+	if total > 0 {
+		usage = fmt.Sprintf("%s in this category.", m.habitCount())
+	}
+
+	var rows []string
+	for i, option := range m.deleteOptions() {
+		var label string
+		switch option {
+		case deleteMove:
+			label = "Move the habits to another category"
+		case deleteMerge:
+			label = "Merge into another category"
+		case deleteAnyway:
+			label = "Delete"
+			if total > 0 {
+				label = "Delete anyway, leaving habits uncategorized"
+			}
+		}
+
+		if i == m.deleteCursor {
+			style := ui.SelectedItem
+			if option == deleteAnyway && total > 0 {
+				style = lipgloss.NewStyle().Foreground(ui.Danger).Bold(true)
+			}
+			rows = append(rows, "> "+style.Render(label))
+		} else {
+			rows = append(rows, "  "+ui.NormalItem.Render(label))
+		}
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		question,
+		usage,
+		"",
+		lipgloss.JoinVertical(lipgloss.Left, rows...),
+		"",
+		ui.HelpLine(m.keys.Select, m.keys.Back),
+	)
+}
+
+func (m Model) renderPickTargetContent() string {
+	cat := m.deleting
+
+	question := fmt.Sprintf("Move the habits in '%s' to:", cat.Name)
+	if m.deleteAction == deleteMerge {
+		question = fmt.Sprintf("Merge '%s' into:", cat.Name)
+	}
+
+	targets := m.deleteTargets()
+	rows := make([]string, len(targets))
+	for i, target := range targets {
+		cursor := "  "
+		name := ui.NormalItem.Render(target.Name)
+		if i == m.targetList.Cursor {
+			cursor = "> "
+			name = ui.SelectedItem.Render(target.Name)
+		}
+		if target.Emoji != "" {
+			name = target.Emoji + " " + name
+		}
+		rows[i] = cursor + name
+	}
+
+	return question + "\n\n" + m.targetList.Render(rows) + "\n" + ui.HelpLine(m.keys.Select, m.keys.Back)
+}
+
+// deleteHelpSections returns the bindings for the delete dialog
+func (m Model) deleteHelpSections() []ui.HelpSection {
+	if m.mode == modePickTarget {
+		return []ui.HelpSection{
+			{Title: "Pick Category", Bindings: []key.Binding{m.keys.Up, m.keys.Down, m.keys.Select, m.keys.Back}},
+		}
+	}
+	return []ui.HelpSection{
+		{Title: "Delete Category", Bindings: []key.Binding{m.keys.Up, m.keys.Down, m.keys.Select, m.keys.Back}},
+	}
+}
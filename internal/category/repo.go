@@ -1,6 +1,7 @@
 package category
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/vittolewerissa/hbt/internal/shared/db"
@@ -18,9 +19,9 @@ func NewRepository(database *db.DB) *Repository {
 }
 
 // List returns all categories
-func (r *Repository) List() ([]model.Category, error) {
-	query := `SELECT id, name, color, emoji, created_at FROM categories ORDER BY name`
-	rows, err := r.db.Query(query)
+func (r *Repository) List(ctx context.Context) ([]model.Category, error) {
+	query := `SELECT id, name, color, emoji, position, created_at FROM categories ORDER BY position, name`
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -29,7 +30,7 @@ func (r *Repository) List() ([]model.Category, error) {
 	var categories []model.Category
 	for rows.Next() {
 		var c model.Category
-		if err := rows.Scan(&c.ID, &c.Name, &c.Color, &c.Emoji, &c.CreatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.Color, &c.Emoji, &c.Position, &c.CreatedAt); err != nil {
 			return nil, err
 		}
 		categories = append(categories, c)
@@ -38,10 +39,10 @@ func (r *Repository) List() ([]model.Category, error) {
 }
 
 // GetByID returns a category by ID
-func (r *Repository) GetByID(id int64) (*model.Category, error) {
-	query := `SELECT id, name, color, emoji, created_at FROM categories WHERE id = ?`
+func (r *Repository) GetByID(ctx context.Context, id int64) (*model.Category, error) {
+	query := `SELECT id, name, color, emoji, position, created_at FROM categories WHERE id = ?`
 	var c model.Category
-	err := r.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Color, &c.Emoji, &c.CreatedAt)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&c.ID, &c.Name, &c.Color, &c.Emoji, &c.Position, &c.CreatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -52,30 +53,145 @@ func (r *Repository) GetByID(id int64) (*model.Category, error) {
 }
 
 // Create creates a new category
-func (r *Repository) Create(c *model.Category) error {
-	query := `INSERT INTO categories (name, color, emoji) VALUES (?, ?, ?)`
-	result, err := r.db.Exec(query, c.Name, c.Color, c.Emoji)
+func (r *Repository) Create(ctx context.Context, c *model.Category) error {
+	query := `
+		INSERT INTO categories (name, color, emoji, position, created_at)
+		VALUES (?, ?, ?, (SELECT COALESCE(MAX(position), -1) + 1 FROM categories), ?)
+	`
+	return r.db.LogChange(ctx, db.EntityCategory, nil, func(tx *sql.Tx) (any, error) {
+		result, err := tx.ExecContext(ctx, query, c.Name, c.Color, c.Emoji, r.db.Now())
+		if err != nil {
+			return nil, err
+		}
+		c.ID, err = result.LastInsertId()
+		return c.ID, err
+	})
+}
+
+// Update updates an existing category
+func (r *Repository) Update(ctx context.Context, c *model.Category) error {
+	query := `UPDATE categories SET name = ?, color = ?, emoji = ? WHERE id = ?`
+	return r.db.ExecLogged(ctx, db.EntityCategory, c.ID, query, c.Name, c.Color, c.Emoji, c.ID)
+}
+
+// SetOrder stores the manual order of categories, giving each ID in ids its index as position
+func (r *Repository) SetOrder(ctx context.Context, ids []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	id, err := result.LastInsertId()
+	defer tx.Rollback()
+
+	for i, id := range ids {
+		if err := r.db.ExecChange(ctx, tx, db.EntityCategory, id, `UPDATE categories SET position = ? WHERE id = ?`, i, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CountHabits returns the number of active and archived habits in a category
+func (r *Repository) CountHabits(ctx context.Context, id int64) (active, archived int, err error) {
+	query := `
+		SELECT
+			COUNT(CASE WHEN archived_at IS NULL THEN 1 END),
+			COUNT(CASE WHEN archived_at IS NOT NULL THEN 1 END)
+		FROM habits WHERE category_id = ?
+	`
+	err = r.db.QueryRowContext(ctx, query, id).Scan(&active, &archived)
+	return active, archived, err
+}
+
+// DeletedCategory records what deleting a category changed, so the delete
+// can be undone
+type DeletedCategory struct {
+	Category model.Category
+	HabitIDs []int64         // habits that were in the category, archived included
+	Target   *model.Category // the merge target as it was before the merge
+}
+
+// Delete removes a category, moving its habits to target in the same
+// transaction. A nil target leaves the habits uncategorized. When merge is
+// set, target also takes over the deleted category's place in the manual
+// order and its emoji if target has none.
+func (r *Repository) Delete(ctx context.Context, id int64, target *int64, merge bool) (*DeletedCategory, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	c.ID = id
-	return nil
+	defer tx.Rollback()
+
+	d := &DeletedCategory{}
+	if d.Category, err = getCategory(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	if merge && target != nil {
+		t, err := getCategory(ctx, tx, *target)
+		if err != nil {
+			return nil, err
+		}
+		d.Target = &t
+	}
+
+	if d.HabitIDs, err = db.QueryIDs(ctx, tx, `SELECT id FROM habits WHERE category_id = ?`, id); err != nil {
+		return nil, err
+	}
+	for _, habitID := range d.HabitIDs {
+		if err := r.db.ExecChange(ctx, tx, db.EntityHabit, habitID, `UPDATE habits SET category_id = ? WHERE id = ?`, target, habitID); err != nil {
+			return nil, err
+		}
+	}
+
+	if merge && target != nil {
+		query := `
+			UPDATE categories SET
+				position = MIN(position, (SELECT position FROM categories WHERE id = ?)),
+				emoji = CASE WHEN emoji = '' THEN (SELECT emoji FROM categories WHERE id = ?) ELSE emoji END
+			WHERE id = ?
+		`
+		if err := r.db.ExecChange(ctx, tx, db.EntityCategory, *target, query, id, id, *target); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.db.ExecChange(ctx, tx, db.EntityCategory, id, `DELETE FROM categories WHERE id = ?`, id); err != nil {
+		return nil, err
+	}
+	return d, tx.Commit()
 }
 
-// Update updates an existing category
-func (r *Repository) Update(c *model.Category) error {
-	query := `UPDATE categories SET name = ?, color = ?, emoji = ? WHERE id = ?`
-	_, err := r.db.Exec(query, c.Name, c.Color, c.Emoji, c.ID)
-	return err
+// Restore undoes a Delete: the category comes back with its original ID
+// and place, its habits move back into it, and a merge target gets its old
+// position and emoji back
+func (r *Repository) Restore(ctx context.Context, d *DeletedCategory) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	c := d.Category
+	query := `INSERT INTO categories (id, name, color, emoji, position, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	if err := r.db.ExecChange(ctx, tx, db.EntityCategory, c.ID, query, c.ID, c.Name, c.Color, c.Emoji, c.Position, db.Timestamp(c.CreatedAt)); err != nil {
+		return err
+	}
+	for _, habitID := range d.HabitIDs {
+		if err := r.db.ExecChange(ctx, tx, db.EntityHabit, habitID, `UPDATE habits SET category_id = ? WHERE id = ?`, c.ID, habitID); err != nil {
+			return err
+		}
+	}
+	if t := d.Target; t != nil {
+		if err := r.db.ExecChange(ctx, tx, db.EntityCategory, t.ID, `UPDATE categories SET position = ?, emoji = ? WHERE id = ?`, t.Position, t.Emoji, t.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-// Delete removes a category
-func (r *Repository) Delete(id int64) error {
-	query := `DELETE FROM categories WHERE id = ?`
-	_, err := r.db.Exec(query, id)
-	return err
+// getCategory reads a category inside a transaction
+func getCategory(ctx context.Context, tx *sql.Tx, id int64) (model.Category, error) {
+	query := `SELECT id, name, color, emoji, position, created_at FROM categories WHERE id = ?`
+	var c model.Category
+	err := tx.QueryRowContext(ctx, query, id).Scan(&c.ID, &c.Name, &c.Color, &c.Emoji, &c.Position, &c.CreatedAt)
+	return c, err
 }
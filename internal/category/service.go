@@ -1,6 +1,9 @@
 package category
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/vittolewerissa/hbt/internal/shared/db"
 	"github.com/vittolewerissa/hbt/internal/shared/model"
 )
@@ -18,31 +21,84 @@ func NewService(database *db.DB) *Service {
 }
 
 // List returns all categories
-func (s *Service) List() ([]model.Category, error) {
-	return s.repo.List()
+func (s *Service) List(ctx context.Context) ([]model.Category, error) {
+	return s.repo.List(ctx)
 }
 
 // Get returns a category by ID
-func (s *Service) Get(id int64) (*model.Category, error) {
-	return s.repo.GetByID(id)
+func (s *Service) Get(ctx context.Context, id int64) (*model.Category, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
 // Create creates a new category
-func (s *Service) Create(c *model.Category) error {
-	// Set default color if not provided (kept for DB compatibility)
+func (s *Service) Create(ctx context.Context, c *model.Category) error {
+	// Without a color, take the next one from the palette
 	if c.Color == "" {
-		c.Color = "#CCCCCC"
+		categories, err := s.repo.List(ctx)
+		if err != nil {
+			return err
+		}
+		c.Color = model.NextColor(categories)
+	}
+	if err := normalizeColor(c); err != nil {
+		return err
 	}
 	// Emoji is optional - allow empty string
-	return s.repo.Create(c)
+	return s.repo.Create(ctx, c)
 }
 
 // Update updates an existing category
-func (s *Service) Update(c *model.Category) error {
-	return s.repo.Update(c)
+func (s *Service) Update(ctx context.Context, c *model.Category) error {
+	if err := normalizeColor(c); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, c)
+}
+
+// normalizeColor stores c's color in the "#RRGGBB" form, or rejects it if
+// it isn't a hex color
+func normalizeColor(c *model.Category) error {
+	color, ok := model.NormalizeColor(c.Color)
+	if !ok {
+		return fmt.Errorf("invalid color %q, want a hex color like #4ECDC4", c.Color)
+	}
+	c.Color = color
+	return nil
+}
+
+// Reorder stores a new manual order for the given categories
+func (s *Service) Reorder(ctx context.Context, ids []int64) error {
+	return s.repo.SetOrder(ctx, ids)
+}
+
+// CountHabits returns the number of active and archived habits in a category
+func (s *Service) CountHabits(ctx context.Context, id int64) (active, archived int, err error) {
+	return s.repo.CountHabits(ctx, id)
+}
+
+// Delete removes a category, leaving its habits uncategorized
+func (s *Service) Delete(ctx context.Context, id int64) (*DeletedCategory, error) {
+	return s.repo.Delete(ctx, id, nil, false)
+}
+
+// MoveAndDelete moves a category's habits to target, then removes the category
+func (s *Service) MoveAndDelete(ctx context.Context, id, target int64) (*DeletedCategory, error) {
+	if id == target {
+		return nil, fmt.Errorf("can't move habits to the category being deleted")
+	}
+	return s.repo.Delete(ctx, id, &target, false)
+}
+
+// Merge folds a category into target: its habits move to target, target
+// takes its place in the order, and the category is removed
+func (s *Service) Merge(ctx context.Context, id, target int64) (*DeletedCategory, error) {
+	if id == target {
+		return nil, fmt.Errorf("can't merge a category into itself")
+	}
+	return s.repo.Delete(ctx, id, &target, true)
 }
 
-// Delete removes a category
-func (s *Service) Delete(id int64) error {
-	return s.repo.Delete(id)
+// Restore undoes a delete, move or merge
+func (s *Service) Restore(ctx context.Context, d *DeletedCategory) error {
+	return s.repo.Restore(ctx, d)
 }
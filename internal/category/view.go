@@ -1,13 +1,16 @@
 package category
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/history"
+	"github.com/vittolewerissa/hbt/internal/settings"
 	"github.com/vittolewerissa/hbt/internal/shared/db"
 	"github.com/vittolewerissa/hbt/internal/shared/model"
 	"github.com/vittolewerissa/hbt/internal/shared/ui"
@@ -20,42 +23,61 @@ const (
 	modeList viewMode = iota
 	modeForm
 	modeConfirmDelete
+	modePickTarget
 )
 
 // Model is the categories tab model
 type Model struct {
-	service    *Service
-	categories []model.Category
-	cursor     int
-	mode       viewMode
-	form       *FormModel
-	width      int
-	height     int
-	keys       ui.KeyMap
-	err        error
+	service      *Service
+	settings     *settings.Service
+	loads        *ui.Loader
+	categories   []model.Category
+	list         ui.ScrollList
+	mode         viewMode
+	form         *FormModel
+	emojiPrefs   settings.EmojiPrefs
+	deleting     model.Category   // category the delete dialog asks about
+	usage        CategoryUsageMsg // habit counts for the delete dialog
+	deleteCursor int
+	deleteAction deleteOption
+	targetList   ui.ScrollList
+	width        int
+	height       int
+	panelHeight  int
+	keys         ui.KeyMap
+	err          error
 }
 
+// Category form fields, in tab order
+const (
+	fieldName = iota
+	fieldEmoji
+	fieldColor
+	fieldHex
+	formFields
+)
+
 // FormModel handles category creation/editing
 type FormModel struct {
-	category       *model.Category
-	nameInput      textinput.Model
-	emojiSearch    textinput.Model
-	selectedEmoji  string
-	emojiIndex     int
-	scrollOffset   int  // For scrolling through emoji list
-	focusIndex     int  // 0: name, 1: emoji button
-	showEmojiModal bool
-	width          int
-	height         int
-	cancelled      bool
-	submitted      bool
+	category    *model.Category
+	nameInput   textinput.Model
+	emojiPicker ui.EmojiPicker
+	color       colorField
+	focusIndex  int // one of the field constants
+	keys        ui.KeyMap
+	width       int
+	height      int
+	cancelled   bool
+	submitted   bool
 }
 
 // New creates a new categories model
-func New(database *db.DB) Model {
+func New(database *db.DB, keys ui.KeyMap) Model {
 	return Model{
-		service: NewService(database),
-		keys:    ui.DefaultKeyMap,
+		service:  NewService(database),
+		settings: settings.NewService(database),
+		loads:    &ui.Loader{},
+		keys:     keys,
 	}
 }
 
@@ -67,37 +89,80 @@ func (m Model) Init() tea.Cmd {
 // CategoriesLoadedMsg is sent when categories are loaded
 type CategoriesLoadedMsg struct {
 	Categories []model.Category
+	EmojiPrefs settings.EmojiPrefs
 	Err        error
 }
 
 // CategorySavedMsg is sent when a category is saved
 type CategorySavedMsg struct {
 	Category *model.Category
+	Change   *history.Change
 	Err      error
 }
 
 // CategoryDeletedMsg is sent when a category is deleted
 type CategoryDeletedMsg struct {
+	Change *history.Change
+	Err    error
+}
+
+// EmojiPrefsSavedMsg is sent when the emoji picker's recents or skin tone
+// have been stored
+type EmojiPrefsSavedMsg struct {
+	Prefs settings.EmojiPrefs
+	Err   error
+}
+
+// CategoryMovedMsg is sent when a category was moved in the manual order
+type CategoryMovedMsg struct {
 	Err error
 }
 
 func (m Model) loadData() tea.Msg {
-	categories, err := m.service.List()
+	ctx, cancel := m.loads.Start()
+	defer cancel()
+	categories, err := m.service.List(ctx)
 	if err != nil {
 		return CategoriesLoadedMsg{Err: err}
 	}
-	return CategoriesLoadedMsg{Categories: categories}
+	prefs, err := m.settings.EmojiPrefs(ctx)
+	if err != nil {
+		return CategoriesLoadedMsg{Err: err}
+	}
+	return CategoriesLoadedMsg{Categories: categories, EmojiPrefs: prefs}
+}
+
+// saveEmojiPrefs stores a change to the emoji picker's state and reads
+// back the result
+func (m Model) saveEmojiPrefs(save func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		if err := save(ctx); err != nil {
+			return EmojiPrefsSavedMsg{Err: err}
+		}
+		prefs, err := m.settings.EmojiPrefs(ctx)
+		return EmojiPrefsSavedMsg{Prefs: prefs, Err: err}
+	}
 }
 
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case CategoriesLoadedMsg:
+		if ui.Superseded(msg.Err) {
+			return m, nil
+		}
 		if msg.Err != nil {
 			m.err = msg.Err
 			return m, nil
 		}
+		m.err = nil
 		m.categories = msg.Categories
+		m.emojiPrefs = msg.EmojiPrefs
+		m.list.Clamp(len(m.categories))
+		m.syncScroll()
+		m.syncDeleteDialog()
 		return m, nil
 
 	case CategorySavedMsg:
@@ -109,14 +174,42 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.form = nil
 		return m, m.loadData
 
+	case EmojiPrefsSavedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.emojiPrefs = msg.Prefs
+		return m, nil
+
+	case ui.EmojiPickedMsg:
+		return m, m.saveEmojiPrefs(func(ctx context.Context) error {
+			return m.settings.UseEmoji(ctx, msg.Emoji)
+		})
+
+	case ui.SkinToneChangedMsg:
+		return m, m.saveEmojiPrefs(func(ctx context.Context) error {
+			return m.settings.SetSkinTone(ctx, msg.Tone)
+		})
+
+	case CategoryMovedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		return m, m.loadData
+
+	case CategoryUsageMsg:
+		return m.handleUsage(msg), nil
+
 	case CategoryDeletedMsg:
 		if msg.Err != nil {
 			m.err = msg.Err
 			return m, nil
 		}
 		m.mode = modeList
-		if m.cursor >= len(m.categories)-1 && m.cursor > 0 {
-			m.cursor--
+		if m.list.Cursor >= len(m.categories)-1 && m.list.Cursor > 0 {
+			m.list.Cursor--
 		}
 		return m, m.loadData
 
@@ -143,77 +236,195 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, cmd
 		}
 		return m.handleKey(msg)
+
+	case ui.ClickMsg:
+		if m.mode == modeList && m.err == nil {
+			if row := m.list.RowAt(msg.Row, len(m.categories)); row >= 0 {
+				m.list.Cursor = row
+				m.syncScroll()
+			}
+		}
+		if m.mode == modePickTarget {
+			if row := m.targetList.RowAt(msg.Row-2, len(m.deleteTargets())); row >= 0 { // -2 for the question
+				m.targetList.Cursor = row
+				m.syncTargetScroll()
+			}
+		}
+
+	case ui.ScrollMsg:
+		switch m.mode {
+		case modeList:
+			m.list.Move(msg.Delta, len(m.categories))
+			m.syncScroll()
+		case modePickTarget:
+			m.targetList.Move(msg.Delta, len(m.deleteTargets()))
+			m.syncTargetScroll()
+		case modeForm:
+			if m.form != nil {
+				*m.form, _ = m.form.Update(msg)
+			}
+		}
+
+	case ui.ModalClickMsg:
+		if m.mode == modeForm && m.form != nil {
+			var cmd tea.Cmd
+			*m.form, cmd = m.form.Update(msg)
+			return m, cmd
+		}
 	}
 
 	return m, nil
 }
 
+// openForm shows the form for adding a category, or editing c if it's not
+// nil. New categories start on the next unused palette color.
+func (m *Model) openForm(c *model.Category) tea.Cmd {
+	m.form = NewCategoryForm(c, model.NextColor(m.categories), m.keys, m.width, m.height)
+	m.form.emojiPicker.SetPrefs(m.emojiPrefs.Recents.Emojis(), m.emojiPrefs.Tone)
+	m.mode = modeForm
+	return m.form.Init()
+}
+
 func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch m.mode {
 	case modeList:
+		if m.list.HandleKey(msg, m.keys, len(m.categories)) {
+			m.syncScroll()
+			return m, nil
+		}
+
 		switch {
-		case key.Matches(msg, m.keys.Up):
-			if m.cursor > 0 {
-				m.cursor--
-			}
-		case key.Matches(msg, m.keys.Down):
-			if m.cursor < len(m.categories)-1 {
-				m.cursor++
-			}
+		case key.Matches(msg, m.keys.MoveUp):
+			return m, m.moveSelected(-1)
+		case key.Matches(msg, m.keys.MoveDown):
+			return m, m.moveSelected(1)
 		case key.Matches(msg, m.keys.Add):
-			m.form = NewCategoryForm(nil, m.width, m.height)
-			m.mode = modeForm
-			return m, m.form.Init()
+			return m, m.openForm(nil)
 		case key.Matches(msg, m.keys.Edit):
 			if len(m.categories) > 0 {
-				cat := m.categories[m.cursor]
-				m.form = NewCategoryForm(&cat, m.width, m.height)
-				m.mode = modeForm
-				return m, m.form.Init()
+				cat := m.categories[m.list.Cursor]
+				return m, m.openForm(&cat)
 			}
 		case key.Matches(msg, m.keys.Delete):
 			if len(m.categories) > 0 {
-				m.mode = modeConfirmDelete
+				return m, m.countHabits(m.categories[m.list.Cursor].ID)
 			}
 		}
 
 	case modeConfirmDelete:
-		switch {
-		case key.Matches(msg, m.keys.Confirm):
-			if len(m.categories) > 0 {
-				return m, m.deleteCategory(m.categories[m.cursor].ID)
-			}
-		case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Back):
-			m.mode = modeList
-		}
+		return m.handleDeleteKey(msg)
+
+	case modePickTarget:
+		return m.handleTargetKey(msg)
 	}
 
 	return m, nil
 }
 
+// SetHeight sets the number of rows available for the tab's panel content
+func (m *Model) SetHeight(height int) {
+	m.panelHeight = height
+	m.syncScroll()
+	m.syncTargetScroll()
+}
+
+// syncScroll sizes the list to the panel, leaving room for the key hints,
+// and keeps the selected category in view
+func (m *Model) syncScroll() {
+	m.list.Height = 0
+	if m.panelHeight > 0 {
+		m.list.Height = max(1, m.panelHeight-2)
+	}
+	m.list.Follow(m.list.Cursor, m.list.Cursor, len(m.categories))
+}
+
+// moveSelected swaps the selected category with its neighbour (delta -1 for
+// up, 1 for down) and stores the new order
+func (m *Model) moveSelected(delta int) tea.Cmd {
+	from, to := m.list.Cursor, m.list.Cursor+delta
+	if from < 0 || from >= len(m.categories) || to < 0 || to >= len(m.categories) {
+		return nil
+	}
+
+	ids := make([]int64, len(m.categories))
+	for i, c := range m.categories {
+		ids[i] = c.ID
+	}
+	ids[from], ids[to] = ids[to], ids[from]
+	m.list.Cursor = to
+
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		return CategoryMovedMsg{Err: m.service.Reorder(ctx, ids)}
+	}
+}
+
 func (m Model) saveCategory(c *model.Category) tea.Cmd {
+	if c.ID == 0 {
+		return m.createCategory(c)
+	}
 	return func() tea.Msg {
-		var err error
-		if c.ID == 0 {
-			err = m.service.Create(c)
-		} else {
-			err = m.service.Update(c)
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		before, err := m.service.Get(ctx, c.ID)
+		if err == nil && before == nil {
+			err = sql.ErrNoRows
+		}
+		if err == nil {
+			err = m.service.Update(ctx, c)
 		}
-		return CategorySavedMsg{Category: c, Err: err}
+		if err != nil {
+			return CategorySavedMsg{Category: c, Err: err}
+		}
+		after := *c
+		return CategorySavedMsg{Category: c, Change: &history.Change{
+			Label: fmt.Sprintf("Updated '%s'", c.Name),
+			Undo:  func(ctx context.Context) error { return m.service.Update(ctx, before) },
+			Redo:  func(ctx context.Context) error { return m.service.Update(ctx, &after) },
+		}}
 	}
 }
 
-func (m Model) deleteCategory(id int64) tea.Cmd {
+// createCategory adds a category. Undoing deletes it; redoing puts it back
+// with the same ID.
+func (m Model) createCategory(c *model.Category) tea.Cmd {
 	return func() tea.Msg {
-		err := m.service.Delete(id)
-		return CategoryDeletedMsg{Err: err}
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		err := m.service.Create(ctx, c)
+		var created *model.Category
+		if err == nil {
+			created, err = m.service.Get(ctx, c.ID)
+		}
+		if err == nil && created == nil {
+			err = sql.ErrNoRows
+		}
+		if err != nil {
+			return CategorySavedMsg{Category: c, Err: err}
+		}
+		deleted := &DeletedCategory{Category: *created}
+		return CategorySavedMsg{Category: c, Change: &history.Change{
+			Label: fmt.Sprintf("Created '%s'", c.Name),
+			Undo: func(ctx context.Context) error {
+				_, err := m.service.Delete(ctx, created.ID)
+				return err
+			},
+			Redo: func(ctx context.Context) error { return m.service.Restore(ctx, deleted) },
+		}}
 	}
 }
 
+func (m Model) deleteCategory(c model.Category) tea.Cmd {
+	return m.removeCategory(fmt.Sprintf("Deleted '%s'", c.Name), func(ctx context.Context) (*DeletedCategory, error) {
+		return m.service.Delete(ctx, c.ID)
+	})
+}
+
 // ViewContent renders just the content without title
 func (m Model) ViewContent() string {
 	if m.err != nil {
-		return ui.MutedText.Render(fmt.Sprintf("Error: %v", m.err))
+		return ui.MutedText.Render(fmt.Sprintf("Error: %v", db.Explain(m.err)))
 	}
 
 	switch m.mode {
@@ -223,6 +434,8 @@ func (m Model) ViewContent() string {
 		}
 	case modeConfirmDelete:
 		return m.renderConfirmDeleteContent()
+	case modePickTarget:
+		return m.renderPickTargetContent()
 	}
 
 	return m.renderListContent()
@@ -236,42 +449,35 @@ func (m Model) renderListContent() string {
 		return s
 	}
 
+	rows := make([]string, len(m.categories))
 	for i, cat := range m.categories {
 		cursor := "  "
-		if i == m.cursor {
+		if i == m.list.Cursor {
 			cursor = "> "
 		}
 
 		name := cat.Name
-		if i == m.cursor {
+		if i == m.list.Cursor {
 			name = ui.SelectedItem.Render(name)
 		} else {
-			name = ui.NormalItem.Render(name)
+			name = ui.CategoryStyle(ui.NormalItem, cat.Color).Render(name)
 		}
 
 		// Use custom emoji from category (optional)
+		swatch := ui.ColorSwatch(cat.Color)
 		if cat.Emoji != "" {
-			s += fmt.Sprintf("%s%s %s\n", cursor, cat.Emoji, name)
+			rows[i] = fmt.Sprintf("%s%s %s %s", cursor, swatch, cat.Emoji, name)
 		} else {
-			s += fmt.Sprintf("%s%s\n", cursor, name)
+			rows[i] = fmt.Sprintf("%s%s %s", cursor, swatch, name)
 		}
 	}
+	s += m.list.Render(rows)
 
-	s += "\n" + ui.MutedText.Render("a: add  e: edit  d: delete")
+	s += "\n" + ui.HelpLine(m.keys.Add, m.keys.Edit, m.keys.Delete, m.keys.MoveDown, m.keys.MoveUp)
 
 	return s
 }
 
-func (m Model) renderConfirmDeleteContent() string {
-	cat := m.categories[m.cursor]
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		fmt.Sprintf("Are you sure you want to delete '%s'?", cat.Name),
-		"",
-		ui.MutedText.Render("y: confirm  n: cancel"),
-	)
-}
-
 // Focused returns whether this view should receive key events
 func (m Model) Focused() bool {
 	return m.mode == modeForm
@@ -279,15 +485,7 @@ func (m Model) Focused() bool {
 
 // HasModal returns true if showing a modal dialog
 func (m Model) HasModal() bool {
-	return m.mode == modeForm && m.form != nil && m.form.showEmojiModal
-}
-
-// RenderModal renders the modal overlay (if any) centered on full screen
-func (m Model) RenderModal(width, height int) string {
-	if !m.HasModal() {
-		return ""
-	}
-	return m.form.renderFullScreenModal(width, height)
+	return m.mode == modeForm && m.form != nil && m.form.emojiPicker.IsOpen()
 }
 
 // RenderModalContent renders just the modal box content for overlay
@@ -295,35 +493,33 @@ func (m Model) RenderModalContent() string {
 	if !m.HasModal() {
 		return ""
 	}
-	return m.form.renderModalBox()
+	return m.form.emojiPicker.View(m.keys)
 }
 
-// NewCategoryForm creates a new category form
-func NewCategoryForm(c *model.Category, width, height int) *FormModel {
+// NewCategoryForm creates a new category form. New categories start on
+// color; edited ones keep their own.
+func NewCategoryForm(c *model.Category, color string, keys ui.KeyMap, width, height int) *FormModel {
 	nameInput := textinput.New()
 	nameInput.Placeholder = "Category name"
 	nameInput.Focus()
 	nameInput.CharLimit = 50
 	nameInput.Width = 30
 
-	emojiSearch := textinput.New()
-	emojiSearch.Placeholder = "Search emojis..."
-	emojiSearch.CharLimit = 50
-	emojiSearch.Width = 30
-
 	selectedEmoji := "" // Empty by default (optional)
 	if c != nil {
 		nameInput.SetValue(c.Name)
 		selectedEmoji = c.Emoji // Keep existing emoji if editing
+		color = c.Color
 	}
 
 	return &FormModel{
-		category:      c,
-		nameInput:     nameInput,
-		emojiSearch:   emojiSearch,
-		selectedEmoji: selectedEmoji,
-		width:         width,
-		height:        height,
+		category:    c,
+		nameInput:   nameInput,
+		emojiPicker: ui.NewEmojiPicker(selectedEmoji),
+		color:       newColorField(color),
+		keys:        keys,
+		width:       width,
+		height:      height,
 	}
 }
 
@@ -335,237 +531,105 @@ func (f *FormModel) Update(msg tea.Msg) (FormModel, tea.Cmd) {
 	var cmd tea.Cmd
 
 	// Handle emoji modal
-	if f.showEmojiModal {
+	if f.emojiPicker.IsOpen() {
 		switch msg := msg.(type) {
+		case ui.ScrollMsg:
+			f.emojiPicker.Scroll(msg.Delta)
+		case ui.ModalClickMsg:
+			cmd = f.emojiPicker.Click(msg, f.keys)
 		case tea.KeyMsg:
-			switch msg.String() {
-			case "esc":
-				f.showEmojiModal = false
-				f.emojiSearch.SetValue("")
-				f.emojiIndex = 0
-				f.scrollOffset = 0
-				f.focusIndex = 1 // Return to emoji field
-				f.nameInput.Blur()
-				return *f, nil
-			case "enter":
-				// Select current emoji or clear it
-				if f.emojiIndex == -1 {
-					// Clear emoji
-					f.selectedEmoji = ""
-				} else {
-					filtered := f.getFilteredEmojis()
-					if len(filtered) > 0 && f.emojiIndex < len(filtered) {
-						f.selectedEmoji = filtered[f.emojiIndex]
-					}
-				}
-				f.showEmojiModal = false
-				f.emojiSearch.SetValue("")
-				f.emojiIndex = 0
-				f.scrollOffset = 0
-				f.focusIndex = 1 // Return to emoji field
-				f.nameInput.Blur()
-				return *f, nil
-			case "left":
-				f.emojiIndex--
-				if f.emojiIndex < -1 {
-					filtered := f.getFilteredEmojis()
-					f.emojiIndex = len(filtered) - 1
-				}
-				f.ensureVisibleEmoji()
-			case "right":
-				filtered := f.getFilteredEmojis()
-				f.emojiIndex++
-				if f.emojiIndex >= len(filtered) {
-					f.emojiIndex = -1
-				}
-				f.ensureVisibleEmoji()
-			case "up":
-				if f.emojiIndex == -1 {
-					// Already at (none), can't go up
-					return *f, nil
-				} else if f.emojiIndex < 8 {
-					// In first row, go to (none)
-					f.emojiIndex = -1
-					f.ensureVisibleEmoji()
-				} else {
-					// Go up one row
-					f.emojiIndex -= 8
-					f.ensureVisibleEmoji()
-				}
-			case "down":
-				filtered := f.getFilteredEmojis()
-				if f.emojiIndex == -1 {
-					// From (none), go to first emoji
-					f.emojiIndex = 0
-					f.ensureVisibleEmoji()
-				} else if f.emojiIndex+8 < len(filtered) {
-					f.emojiIndex += 8
-					f.ensureVisibleEmoji()
-				}
-			case "pgup":
-				// Scroll up by visible rows
-				const maxVisibleRows = 8
-				f.emojiIndex -= 8 * maxVisibleRows
-				if f.emojiIndex < -1 {
-					f.emojiIndex = -1
-				}
-				f.ensureVisibleEmoji()
-			case "pgdown":
-				// Scroll down by visible rows
-				const maxVisibleRows = 8
-				filtered := f.getFilteredEmojis()
-				f.emojiIndex += 8 * maxVisibleRows
-				if f.emojiIndex >= len(filtered) {
-					f.emojiIndex = len(filtered) - 1
-				}
-				f.ensureVisibleEmoji()
-			default:
-				// Update search input
-				f.emojiSearch, cmd = f.emojiSearch.Update(msg)
-				f.emojiIndex = -1 // Reset to (none) when searching
-				f.scrollOffset = 0
-				return *f, cmd
-			}
+			cmd = f.emojiPicker.Update(msg, f.keys)
 		}
-		return *f, nil
+		return *f, cmd
 	}
 
 	// Handle main form
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "esc":
+		switch {
+		case f.matches(msg, f.keys.Back):
 			f.cancelled = true
 			return *f, nil
-		case "enter":
-			if f.focusIndex == 1 {
-				// On emoji field - open picker
-				f.showEmojiModal = true
-				f.emojiSearch.Focus()
-				// Start at (none) if no emoji selected, otherwise start at first emoji
-				if f.selectedEmoji == "" {
-					f.emojiIndex = -1
-				} else {
-					f.emojiIndex = 0
-				}
-				return *f, textinput.Blink
-			} else if f.focusIndex == 0 && f.nameInput.Value() != "" {
+		case f.matches(msg, f.keys.Select):
+			switch f.focusIndex {
+			case fieldName:
 				// On name field with text - move to emoji field
-				f.focusIndex = 1
-				f.nameInput.Blur()
-				return *f, nil
-			}
-		case "ctrl+s":
-			// Ctrl+S to save from anywhere
-			if f.nameInput.Value() != "" {
-				f.submitted = true
-				return *f, nil
+				if f.nameInput.Value() != "" {
+					return *f, f.focus(fieldEmoji)
+				}
+			case fieldEmoji:
+				// On emoji field - open picker
+				return *f, f.emojiPicker.Open()
+			case fieldColor:
+				return *f, f.focus(fieldHex)
+			case fieldHex:
+				f.submit()
 			}
-		case "backspace", "delete":
+			return *f, nil
+		case f.matches(msg, f.keys.Save):
+			// Save from anywhere
+			f.submit()
+			return *f, nil
+		case f.matches(msg, f.keys.Clear) && f.focusIndex == fieldEmoji && f.emojiPicker.Value() != "":
 			// Clear emoji if on emoji field
-			if f.focusIndex == 1 && f.selectedEmoji != "" {
-				f.selectedEmoji = ""
-				return *f, nil
-			}
-		case "tab", "down":
-			// Toggle between fields
-			f.focusIndex = (f.focusIndex + 1) % 2
-			if f.focusIndex == 0 {
-				f.nameInput.Focus()
-				cmd = textinput.Blink
-			} else {
-				f.nameInput.Blur()
-			}
-			return *f, cmd
-		case "shift+tab", "up":
-			// Toggle backwards
-			f.focusIndex = (f.focusIndex - 1 + 2) % 2
-			if f.focusIndex == 0 {
-				f.nameInput.Focus()
-				cmd = textinput.Blink
-			} else {
-				f.nameInput.Blur()
-			}
-			return *f, cmd
-		case " ":
+			f.emojiPicker.Clear()
+			return *f, nil
+		case f.matches(msg, f.keys.NextField):
+			return *f, f.focus((f.focusIndex + 1) % formFields)
+		case f.matches(msg, f.keys.PrevField):
+			return *f, f.focus((f.focusIndex - 1 + formFields) % formFields)
+		case f.matches(msg, f.keys.Toggle) && f.focusIndex == fieldEmoji:
 			// Space on emoji field opens picker
-			if f.focusIndex == 1 {
-				f.showEmojiModal = true
-				f.emojiSearch.Focus()
-				// Start at (none) if no emoji selected, otherwise start at first emoji
-				if f.selectedEmoji == "" {
-					f.emojiIndex = -1
-				} else {
-					f.emojiIndex = 0
-				}
-				return *f, textinput.Blink
-			}
+			return *f, f.emojiPicker.Open()
+		case f.matches(msg, f.keys.Left) && f.focusIndex == fieldColor:
+			f.color.step(-1)
+			return *f, nil
+		case f.matches(msg, f.keys.Right) && f.focusIndex == fieldColor:
+			f.color.step(1)
+			return *f, nil
 		}
 	}
 
-	// Update name input only if focused
-	if f.focusIndex == 0 {
+	// Update the text inputs only if focused
+	switch f.focusIndex {
+	case fieldName:
 		f.nameInput, cmd = f.nameInput.Update(msg)
+	case fieldHex:
+		f.color.hex, cmd = f.color.hex.Update(msg)
 	}
 	return *f, cmd
 }
 
-func (f *FormModel) getFilteredEmojis() []string {
-	search := strings.ToLower(f.emojiSearch.Value())
-	if search == "" {
-		return model.CommonEmojis
-	}
-
-	var filtered []string
-	for _, emoji := range model.CommonEmojis {
-		keywords := model.EmojiKeywords[emoji]
-		if strings.Contains(strings.ToLower(keywords), search) {
-			filtered = append(filtered, emoji)
-		}
-	}
-	return filtered
+// focus moves to a field, focusing its text input if it has one
+func (f *FormModel) focus(field int) tea.Cmd {
+	f.focusIndex = field
+	f.nameInput.Blur()
+	f.color.hex.Blur()
+	switch field {
+	case fieldName:
+		f.nameInput.Focus()
+		return textinput.Blink
+	case fieldHex:
+		f.color.hex.Focus()
+		return textinput.Blink
+	}
+	return nil
 }
 
-// ensureVisibleEmoji ensures the selected emoji is within the visible scroll area
-func (f *FormModel) ensureVisibleEmoji() {
-	const emojisPerRow = 8
-	const maxVisibleRows = 8 // Maximum rows to show at once
-
-	// If (none) is selected, scroll to top
-	if f.emojiIndex == -1 {
-		f.scrollOffset = 0
-		return
-	}
-
-	filtered := f.getFilteredEmojis()
-	if len(filtered) == 0 {
-		return
-	}
-
-	// Calculate current row of selected emoji
-	selectedRow := f.emojiIndex / emojisPerRow
-
-	// Adjust scroll offset to keep selected emoji visible
-	if selectedRow < f.scrollOffset {
-		// Selected emoji is above visible area
-		f.scrollOffset = selectedRow
-	} else if selectedRow >= f.scrollOffset+maxVisibleRows {
-		// Selected emoji is below visible area
-		f.scrollOffset = selectedRow - maxVisibleRows + 1
+// submit saves the form once it has a name and a valid color
+func (f *FormModel) submit() {
+	if _, ok := f.color.value(); ok && f.nameInput.Value() != "" {
+		f.submitted = true
 	}
+}
 
-	// Ensure scroll offset is valid
-	totalRows := (len(filtered) + emojisPerRow - 1) / emojisPerRow
-	maxScroll := totalRows - maxVisibleRows
-	if maxScroll < 0 {
-		maxScroll = 0
-	}
-	if f.scrollOffset > maxScroll {
-		f.scrollOffset = maxScroll
-	}
-	if f.scrollOffset < 0 {
-		f.scrollOffset = 0
+// matches checks a binding against the focused field, letting the name
+// and hex inputs keep printable keys
+func (f *FormModel) matches(msg tea.KeyMsg, b key.Binding) bool {
+	if f.focusIndex == fieldName || f.focusIndex == fieldHex {
+		return ui.MatchesNonText(msg, b)
 	}
+	return key.Matches(msg, b)
 }
 
 func (f *FormModel) ViewContent() string {
@@ -573,7 +637,7 @@ func (f *FormModel) ViewContent() string {
 
 	// Name input
 	nameLabel := "Name:"
-	if f.focusIndex == 0 {
+	if f.focusIndex == fieldName {
 		nameLabel = ui.SelectedItem.Render("Name:")
 	}
 	s += nameLabel + "\n"
@@ -581,138 +645,90 @@ func (f *FormModel) ViewContent() string {
 
 	// Emoji field
 	emojiLabel := "Emoji:"
-	if f.focusIndex == 1 {
+	if f.focusIndex == fieldEmoji {
 		emojiLabel = ui.SelectedItem.Render("Emoji:")
 	}
 
-	var emojiDisplay string
-	if f.selectedEmoji == "" {
-		emojiDisplay = ui.MutedText.Render("(none)")
-	} else {
-		emojiDisplay = f.selectedEmoji
-	}
+	s += emojiLabel + " " + f.emojiPicker.Field(f.focusIndex == fieldEmoji) + "\n\n"
 
-	if f.focusIndex == 1 {
-		if f.selectedEmoji == "" {
-			emojiDisplay = ui.SelectedItem.Render("[(none)]")
-		} else {
-			emojiDisplay = ui.SelectedItem.Render("[" + f.selectedEmoji + "]")
-		}
+	// Color palette and hex
+	colorLabel := "Color:"
+	if f.focusIndex == fieldColor {
+		colorLabel = ui.SelectedItem.Render("Color:")
 	}
-	s += emojiLabel + " " + emojiDisplay + "\n\n"
+	s += colorLabel + " " + f.color.paletteView(f.focusIndex == fieldColor) + "\n"
+	hexLabel := "Hex:"
+	if f.focusIndex == fieldHex {
+		hexLabel = ui.SelectedItem.Render("Hex:")
+	}
+	s += hexLabel + "   " + f.color.hex.View() + "\n\n"
+
+	s += "Preview: " + f.preview() + "\n\n"
 
-	s += ui.MutedText.Render("tab: switch fields  enter/space: pick emoji  backspace: clear")
+	s += ui.HelpLine(f.keys.NextField, f.keys.Select, f.keys.Clear)
 	s += "\n"
-	s += ui.MutedText.Render("ctrl+s: save  esc: cancel")
+	s += ui.HelpLine(colorBinding(f.keys), f.keys.Save, f.keys.Back)
 
 	return s
 }
 
-func (f *FormModel) renderModalBox() string {
-	var s string
-
-	// Modal title
-	s += ui.Title.Render("Pick an Emoji") + "\n\n"
-
-	// Search input
-	s += "Search: " + f.emojiSearch.View() + "\n\n"
-
-	// Show (none) option
-	noneText := "(none)"
-	if f.emojiIndex == -1 {
-		s += "[" + noneText + "]" + "\n"
-	} else {
-		s += " " + noneText + " " + "\n"
+// preview renders the category's emoji and name in the chosen color, as
+// its header will look
+func (f *FormModel) preview() string {
+	color, ok := f.color.value()
+	if !ok {
+		return lipgloss.NewStyle().Foreground(ui.Danger).Render("not a hex color, e.g. #4ECDC4")
 	}
-	s += "\n"
-
-	// Show filtered emoji grid - 8 per row, max 8 rows visible
-	const emojisPerRow = 8
-	const maxVisibleRows = 8
-	filtered := f.getFilteredEmojis()
-
-	if len(filtered) == 0 {
-		s += ui.MutedText.Render("No emojis found") + "\n"
-	} else {
-		totalRows := (len(filtered) + emojisPerRow - 1) / emojisPerRow
-		startRow := f.scrollOffset
-		endRow := startRow + maxVisibleRows
-		if endRow > totalRows {
-			endRow = totalRows
-		}
-
-		// Show scroll indicator at top if not at beginning
-		if startRow > 0 {
-			s += ui.MutedText.Render("        ▲ more above ▲") + "\n"
-		}
-
-		// Render visible rows only
-		for row := startRow; row < endRow; row++ {
-			var rowEmojis []string
-			for col := 0; col < emojisPerRow; col++ {
-				idx := row*emojisPerRow + col
-				if idx >= len(filtered) {
-					break
-				}
-				emoji := filtered[idx]
-				display := emoji
-				if idx == f.emojiIndex {
-					display = "[" + emoji + "]"
-				} else {
-					display = " " + emoji + " "
-				}
-				rowEmojis = append(rowEmojis, display)
-			}
-			s += lipgloss.JoinHorizontal(lipgloss.Left, rowEmojis...) + "\n"
-		}
-
-		// Show scroll indicator at bottom if there's more content
-		if endRow < totalRows {
-			s += ui.MutedText.Render("        ▼ more below ▼") + "\n"
-		}
+	title := f.nameInput.Value()
+	if title == "" {
+		title = "Category"
 	}
-	s += "\n"
-
-	s += ui.MutedText.Render("↑↓←→: navigate  pgup/pgdn: scroll  enter: select  esc: cancel")
-
-	// Add modal box styling with dark background
-	modalWidth := 54
-	modalStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(ui.Primary).
-		Background(lipgloss.Color("#1F2937")).
-		Padding(1, 2).
-		Width(modalWidth)
-
-	return modalStyle.Render(s)
+	if e := f.emojiPicker.Value(); e != "" {
+		title = e + " " + title
+	}
+	return ui.CategoryStyle(lipgloss.NewStyle().Bold(true), color).Render(title)
 }
 
-func (f *FormModel) renderFullScreenModal(width, height int) string {
-	modalContent := f.renderModalBox()
-
-	// Center the modal on the full screen with a dark background
-	return lipgloss.Place(
-		width,
-		height,
-		lipgloss.Center,
-		lipgloss.Center,
-		modalContent,
-		lipgloss.WithWhitespaceChars(" "),
-		lipgloss.WithWhitespaceBackground(lipgloss.Color("#0a0e1a")),
+// colorBinding describes the arrow keys that pick from the palette
+func colorBinding(keys ui.KeyMap) key.Binding {
+	return key.NewBinding(
+		key.WithKeys(append(keys.Left.Keys(), keys.Right.Keys()...)...),
+		key.WithHelp(keys.Left.Help().Key+"/"+keys.Right.Help().Key, "color"),
 	)
 }
 
 func (f *FormModel) GetCategory() *model.Category {
+	color, _ := f.color.value()
 	if f.category == nil {
 		return &model.Category{
 			Name:  f.nameInput.Value(),
-			Color: "#CCCCCC", // Default gray color (not shown in UI)
-			Emoji: f.selectedEmoji,
+			Color: color,
+			Emoji: f.emojiPicker.Value(),
 		}
 	}
 
 	f.category.Name = f.nameInput.Value()
-	f.category.Emoji = f.selectedEmoji
-	// Keep existing color if editing
+	f.category.Emoji = f.emojiPicker.Value()
+	f.category.Color = color
 	return f.category
 }
+
+// HelpSections returns the key bindings relevant to the current mode
+func (m Model) HelpSections() []ui.HelpSection {
+	switch m.mode {
+	case modeForm:
+		if m.form != nil && m.form.emojiPicker.IsOpen() {
+			return []ui.HelpSection{ui.EmojiPickerHelp(m.keys)}
+		}
+		return []ui.HelpSection{
+			{Title: "Category Form", Bindings: []key.Binding{
+				m.keys.NextField, m.keys.PrevField, colorBinding(m.keys), m.keys.Select, m.keys.Clear, m.keys.Save, m.keys.Back,
+			}},
+		}
+	case modeConfirmDelete, modePickTarget:
+		return m.deleteHelpSections()
+	}
+	return []ui.HelpSection{
+		{Title: "Categories", Bindings: []key.Binding{m.keys.Up, m.keys.Down, m.keys.Add, m.keys.Edit, m.keys.Delete}},
+	}
+}
@@ -0,0 +1,81 @@
+package checkin
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+// Repository handles check-in database operations
+type Repository struct {
+	db *db.DB
+}
+
+// NewRepository creates a new check-in repository
+func NewRepository(database *db.DB) *Repository {
+	return &Repository{db: database}
+}
+
+// Get returns the check-in for a date, or nil if there is none
+func (r *Repository) Get(ctx context.Context, date time.Time) (*model.CheckIn, error) {
+	query := `SELECT date, mood, energy, notes FROM checkins WHERE date = ?`
+	c, err := scanCheckIn(r.db.QueryRowContext(ctx, query, date.Format("2006-01-02")))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Save stores a check-in, replacing any earlier one for the same date
+func (r *Repository) Save(ctx context.Context, c *model.CheckIn) error {
+	query := `
+		INSERT INTO checkins (date, mood, energy, notes, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(date) DO UPDATE SET
+			mood = excluded.mood, energy = excluded.energy, notes = excluded.notes
+	`
+	date := c.Date.Format("2006-01-02")
+	return r.db.ExecLogged(ctx, db.EntityCheckIn, date, query, date, c.Mood, c.Energy, c.Notes, r.db.Now())
+}
+
+// ListSince returns the check-ins on or after a date, oldest first
+func (r *Repository) ListSince(ctx context.Context, start time.Time) ([]model.CheckIn, error) {
+	query := `SELECT date, mood, energy, notes FROM checkins WHERE date >= ? ORDER BY date`
+	rows, err := r.db.QueryContext(ctx, query, start.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checkIns []model.CheckIn
+	for rows.Next() {
+		c, err := scanCheckIn(rows)
+		if err != nil {
+			return nil, err
+		}
+		checkIns = append(checkIns, *c)
+	}
+	return checkIns, rows.Err()
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCheckIn(row scanner) (*model.CheckIn, error) {
+	var c model.CheckIn
+	var dateStr string
+	var notes sql.NullString
+	if err := row.Scan(&dateStr, &c.Mood, &c.Energy, &notes); err != nil {
+		return nil, err
+	}
+	c.Date, _ = time.Parse("2006-01-02", dateStr)
+	c.Notes = notes.String
+	return &c, nil
+}
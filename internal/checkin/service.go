@@ -0,0 +1,77 @@
+package checkin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/settings"
+	"github.com/vittolewerissa/hbt/internal/shared/clock"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+// Service handles daily check-in logic
+type Service struct {
+	repo     *Repository
+	settings *settings.Service
+	clock    clock.Clock
+}
+
+// NewService creates a new check-in service
+func NewService(database *db.DB) *Service {
+	return &Service{
+		repo:     NewRepository(database),
+		settings: settings.NewService(database),
+		clock:    database.Clock,
+	}
+}
+
+// Today returns today's check-in, or nil if there is none yet
+func (s *Service) Today(ctx context.Context) (*model.CheckIn, error) {
+	return s.repo.Get(ctx, s.clock.Now())
+}
+
+// Due reports whether the check-in should be offered today: there is no
+// check-in yet and it wasn't skipped today
+func (s *Service) Due(ctx context.Context) (bool, error) {
+	c, err := s.Today(ctx)
+	if err != nil || c != nil {
+		return false, err
+	}
+
+	skipped, err := s.settings.Get(ctx, settings.KeyCheckInSkipped)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return skipped != s.clock.Now().Format("2006-01-02"), nil
+}
+
+// Save stores today's check-in
+func (s *Service) Save(ctx context.Context, c *model.CheckIn) error {
+	if c.Mood < model.MinRating || c.Mood > model.MaxRating {
+		return fmt.Errorf("mood must be between %d and %d", model.MinRating, model.MaxRating)
+	}
+	if c.Energy < model.MinRating || c.Energy > model.MaxRating {
+		return fmt.Errorf("energy must be between %d and %d", model.MinRating, model.MaxRating)
+	}
+	if c.Date.IsZero() {
+		c.Date = s.clock.Now()
+	}
+	return s.repo.Save(ctx, c)
+}
+
+// Skip stops the check-in being offered again today
+func (s *Service) Skip(ctx context.Context) error {
+	return s.settings.Set(ctx, settings.KeyCheckInSkipped, s.clock.Now().Format("2006-01-02"))
+}
+
+// ListSince returns the check-ins on or after a date, oldest first
+func (s *Service) ListSince(ctx context.Context, start time.Time) ([]model.CheckIn, error) {
+	return s.repo.ListSince(ctx, start)
+}
@@ -0,0 +1,213 @@
+package habits
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/history"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+)
+
+// archivedRows is the number of lines each archived habit takes
+const archivedRows = 2
+
+// ArchivedLoadedMsg is sent when archived habits are loaded
+type ArchivedLoadedMsg struct {
+	Habits []ArchivedHabit
+	Err    error
+}
+
+// HabitRestoredMsg is sent when an archived habit is restored
+type HabitRestoredMsg struct {
+	Change *history.Change
+	Err    error
+}
+
+// HabitPurgedMsg is sent when an archived habit is permanently deleted
+type HabitPurgedMsg struct {
+	Err error
+}
+
+func (m Model) loadArchived() tea.Msg {
+	ctx, cancel := m.archiveLoads.Start()
+	defer cancel()
+	habits, err := m.service.ListArchived(ctx)
+	return ArchivedLoadedMsg{Habits: habits, Err: err}
+}
+
+func (m Model) restoreHabit(h ArchivedHabit) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		if err := m.service.Unarchive(ctx, h.ID); err != nil {
+			return HabitRestoredMsg{Err: err}
+		}
+		return HabitRestoredMsg{Change: &history.Change{
+			Label: fmt.Sprintf("Restored '%s'", h.Name),
+			Undo:  func(ctx context.Context) error { return m.service.Archive(ctx, h.ID) },
+			Redo:  func(ctx context.Context) error { return m.service.Unarchive(ctx, h.ID) },
+		}}
+	}
+}
+
+func (m Model) purgeHabit(id int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		return HabitPurgedMsg{Err: m.service.Delete(ctx, id)}
+	}
+}
+
+// handleArchiveKey handles keys while browsing archived habits
+func (m Model) handleArchiveKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.archiveList.HandleKey(msg, m.keys, len(m.archived)) {
+		m.syncScroll()
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Archive), key.Matches(msg, m.keys.Back):
+		m.mode = modeList
+		m.syncScroll()
+	case key.Matches(msg, m.keys.Restore):
+		if len(m.archived) > 0 {
+			return m, m.restoreHabit(m.archived[m.archiveList.Cursor])
+		}
+	case key.Matches(msg, m.keys.Delete):
+		if len(m.archived) > 0 {
+			m.purging = m.archived[m.archiveList.Cursor]
+			m.purgeInput = textinput.New()
+			m.purgeInput.Placeholder = m.purging.Name
+			m.purgeInput.CharLimit = 50
+			m.purgeInput.Width = 30
+			m.mode = modeConfirmPurge
+			return m, m.purgeInput.Focus()
+		}
+	}
+	return m, nil
+}
+
+// handlePurgeKey handles the typed confirmation for a permanent delete
+func (m Model) handlePurgeKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case ui.MatchesNonText(msg, m.keys.Back):
+		m.mode = modeArchive
+		return m, nil
+	case ui.MatchesNonText(msg, m.keys.Select):
+		if m.purgeInput.Value() == m.purging.Name {
+			m.mode = modeArchive
+			return m, m.purgeHabit(m.purging.ID)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.purgeInput, cmd = m.purgeInput.Update(msg)
+	return m, cmd
+}
+
+// syncArchiveScroll sizes the archive list to the panel, leaving room for
+// the title and key hints
+func (m *Model) syncArchiveScroll() {
+	m.archiveList.Height = 0
+	if m.panelHeight > 0 {
+		m.archiveList.Height = max(1, m.panelHeight-4)
+	}
+	first := m.archiveList.Cursor * archivedRows
+	m.archiveList.Follow(first, first+archivedRows-1, len(m.archived)*archivedRows)
+}
+
+// archivedAt maps a clicked line of the archive view to an archived habit index
+func (m Model) archivedAt(line int) int {
+	row := m.archiveList.RowAt(line-2, len(m.archived)*archivedRows) // -2 for the title
+	if row < 0 {
+		return -1
+	}
+	return row / archivedRows
+}
+
+func (m Model) renderArchiveContent() string {
+	s := ui.Subtitle.Render("Archived Habits") + "\n"
+
+	if len(m.archived) == 0 {
+		s += ui.MutedText.Render("No archived habits.") + "\n"
+		s += "\n" + ui.HelpLine(m.keys.Back)
+		return s
+	}
+
+	var rows []string
+	for i, habit := range m.archived {
+		cursor := "  "
+		name := ui.NormalItem.Render(habit.Name)
+		if i == m.archiveList.Cursor {
+			cursor = "> "
+			name = ui.SelectedItem.Render(habit.Name)
+		}
+		if habit.Emoji != "" {
+			name = habit.Emoji + " " + name
+		}
+
+		archived := ""
+		if habit.ArchivedAt != nil {
+			archived = "archived " + habit.ArchivedAt.Local().Format("Jan 2, 2006")
+		}
+		rows = append(rows, cursor+name+"  "+ui.MutedText.Render(archived))
+		rows = append(rows, ui.MutedText.Render("    "+formatLifetime(habit.Stats)))
+	}
+	s += m.archiveList.Render(rows)
+
+	s += "\n" + ui.HelpLine(m.keys.Restore, m.keys.Delete, m.keys.Back)
+	return s
+}
+
+// formatLifetime summarizes lifetime stats, e.g. "42 done · best 12 · Jan 2025–Mar 2026"
+func formatLifetime(stats LifetimeStats) string {
+	if stats.Completions == 0 {
+		return "never completed"
+	}
+	s := fmt.Sprintf("%d done · best %d", stats.Completions, stats.BestStreak)
+	if stats.FirstCompleted != nil && stats.LastCompleted != nil {
+		s += fmt.Sprintf(" · %s–%s", stats.FirstCompleted.Format("Jan 2006"), stats.LastCompleted.Format("Jan 2006"))
+	}
+	return s
+}
+
+func (m Model) renderPurgeContent() string {
+	habit := m.purging
+
+	matches := m.purgeInput.Value() == habit.Name
+	hint := ui.MutedText.Render("Type the habit name to confirm")
+	if matches {
+		hint = lipgloss.NewStyle().Foreground(ui.Danger).Render("Press enter to delete forever")
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		fmt.Sprintf("Permanently delete '%s' and all %d completions?", habit.Name, habit.Stats.Completions),
+		ui.MutedText.Render("This can't be undone."),
+		"",
+		ui.FormInputFocused.Render(m.purgeInput.View()),
+		hint,
+		"",
+		ui.HelpLine(m.keys.Select, m.keys.Back),
+	)
+}
+
+// archiveHelpSections returns the bindings for the archive browser
+func (m Model) archiveHelpSections() []ui.HelpSection {
+	if m.mode == modeConfirmPurge {
+		return []ui.HelpSection{
+			{Title: "Delete Forever", Bindings: []key.Binding{m.keys.Select, m.keys.Back}},
+		}
+	}
+	return []ui.HelpSection{
+		{Title: "Archived Habits", Bindings: []key.Binding{
+			m.keys.Up, m.keys.Down, m.keys.Restore, m.keys.Delete, m.keys.Archive, m.keys.Back,
+		}},
+	}
+}
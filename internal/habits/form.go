@@ -3,6 +3,7 @@ package habits
 import (
 	"fmt"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -29,18 +30,15 @@ type FormModel struct {
 	categories         []model.Category
 	nameInput          textinput.Model
 	descInput          textinput.Model
-	emojiSearch        textinput.Model
 	freqValueInput     textinput.Model
 	targetPerDayInput  textinput.Model
 	focusedField       FormField
 	frequencyType      model.FrequencyType
-	selectedEmoji      string
-	emojiIndex         int
-	scrollOffset       int
-	showEmojiModal     bool
+	emojiPicker        ui.EmojiPicker
 	categoryIndex      int // -1 for no category
 	showCategoryModal  bool
 	categoryModalIndex int // Index within modal
+	keys               ui.KeyMap
 	width              int
 	height             int
 	submitted          bool
@@ -49,7 +47,7 @@ type FormModel struct {
 }
 
 // NewForm creates a new form model
-func NewForm(habit *model.Habit, categories []model.Category, width, height int) *FormModel {
+func NewForm(habit *model.Habit, categories []model.Category, keys ui.KeyMap, width, height int) *FormModel {
 	nameInput := textinput.New()
 	nameInput.Placeholder = "Habit name"
 	nameInput.Focus()
@@ -71,20 +69,16 @@ func NewForm(habit *model.Habit, categories []model.Category, width, height int)
 	targetPerDayInput.CharLimit = 2
 	targetPerDayInput.Width = 10
 
-	emojiSearch := textinput.New()
-	emojiSearch.Placeholder = "Search emojis..."
-	emojiSearch.CharLimit = 50
-	emojiSearch.Width = 30
-
 	m := &FormModel{
 		categories:        categories,
 		nameInput:         nameInput,
 		descInput:         descInput,
-		emojiSearch:       emojiSearch,
+		emojiPicker:       ui.NewEmojiPicker(""),
 		freqValueInput:    freqValueInput,
 		targetPerDayInput: targetPerDayInput,
 		frequencyType:     model.FreqDaily,
 		categoryIndex:     -1,
+		keys:              keys,
 		width:             width,
 		height:            height,
 	}
@@ -94,7 +88,7 @@ func NewForm(habit *model.Habit, categories []model.Category, width, height int)
 		m.isEdit = true
 		m.nameInput.SetValue(habit.Name)
 		m.descInput.SetValue(habit.Description)
-		m.selectedEmoji = habit.Emoji
+		m.emojiPicker = ui.NewEmojiPicker(habit.Emoji)
 		m.frequencyType = habit.FrequencyType
 		m.freqValueInput.SetValue(fmt.Sprintf("%d", habit.FrequencyValue))
 		if habit.TargetPerDay > 0 {
@@ -128,106 +122,36 @@ func (m *FormModel) Init() tea.Cmd {
 // Update handles form messages
 func (m *FormModel) Update(msg tea.Msg) (FormModel, tea.Cmd) {
 	switch msg := msg.(type) {
+	case ui.ScrollMsg:
+		m.scrollModal(msg.Delta)
+		return *m, nil
+
+	case ui.ModalClickMsg:
+		return *m, m.clickModal(msg)
+
 	case tea.KeyMsg:
 		// Handle emoji modal if open
-		if m.showEmojiModal {
-			var cmd tea.Cmd
-			switch msg.String() {
-			case "esc":
-				m.showEmojiModal = false
-				m.emojiSearch.SetValue("")
-				m.emojiIndex = 0
-				m.scrollOffset = 0
-				return *m, nil
-			case "enter":
-				// Select current emoji or clear it
-				if m.emojiIndex == -1 {
-					m.selectedEmoji = ""
-				} else {
-					filtered := m.getFilteredEmojis()
-					if len(filtered) > 0 && m.emojiIndex < len(filtered) {
-						m.selectedEmoji = filtered[m.emojiIndex]
-					}
-				}
-				m.showEmojiModal = false
-				m.emojiSearch.SetValue("")
-				m.emojiIndex = 0
-				m.scrollOffset = 0
-				return *m, nil
-			case "left":
-				m.emojiIndex--
-				if m.emojiIndex < -1 {
-					filtered := m.getFilteredEmojis()
-					m.emojiIndex = len(filtered) - 1
-				}
-				m.ensureVisibleEmoji()
-			case "right":
-				filtered := m.getFilteredEmojis()
-				m.emojiIndex++
-				if m.emojiIndex >= len(filtered) {
-					m.emojiIndex = -1
-				}
-				m.ensureVisibleEmoji()
-			case "up":
-				if m.emojiIndex == -1 {
-					return *m, nil
-				} else if m.emojiIndex < 8 {
-					m.emojiIndex = -1
-					m.ensureVisibleEmoji()
-				} else {
-					m.emojiIndex -= 8
-					m.ensureVisibleEmoji()
-				}
-			case "down":
-				filtered := m.getFilteredEmojis()
-				if m.emojiIndex == -1 {
-					m.emojiIndex = 0
-					m.ensureVisibleEmoji()
-				} else if m.emojiIndex+8 < len(filtered) {
-					m.emojiIndex += 8
-					m.ensureVisibleEmoji()
-				}
-			case "pgup":
-				const maxVisibleRows = 8
-				m.emojiIndex -= 8 * maxVisibleRows
-				if m.emojiIndex < -1 {
-					m.emojiIndex = -1
-				}
-				m.ensureVisibleEmoji()
-			case "pgdown":
-				const maxVisibleRows = 8
-				filtered := m.getFilteredEmojis()
-				m.emojiIndex += 8 * maxVisibleRows
-				if m.emojiIndex >= len(filtered) {
-					m.emojiIndex = len(filtered) - 1
-				}
-				m.ensureVisibleEmoji()
-			default:
-				m.emojiSearch, cmd = m.emojiSearch.Update(msg)
-				m.emojiIndex = -1
-				m.scrollOffset = 0
-				return *m, cmd
-			}
-			return *m, nil
+		if m.emojiPicker.IsOpen() {
+			return *m, m.emojiPicker.Update(msg, m.keys)
 		}
 
 		// Handle category modal if open
 		if m.showCategoryModal {
-			switch msg.String() {
-			case "esc":
+			switch {
+			case key.Matches(msg, m.keys.Back):
 				m.showCategoryModal = false
 				return *m, nil
-			case "enter":
+			case key.Matches(msg, m.keys.Select):
 				// Select current category
 				m.categoryIndex = m.categoryModalIndex
 				m.showCategoryModal = false
 				return *m, nil
-			case "up":
+			case key.Matches(msg, m.keys.Up):
 				m.categoryModalIndex--
 				if m.categoryModalIndex < -1 {
 					m.categoryModalIndex = len(m.categories) - 1
 				}
-			case "down":
+			case key.Matches(msg, m.keys.Down):
 				m.categoryModalIndex++
 				if m.categoryModalIndex >= len(m.categories) {
 					m.categoryModalIndex = -1
@@ -236,22 +160,15 @@ func (m *FormModel) Update(msg tea.Msg) (FormModel, tea.Cmd) {
 			return *m, nil
 		}
 
-		switch msg.String() {
-		case "esc":
+		switch {
+		case m.matches(msg, m.keys.Back):
 			m.cancelled = true
 			return *m, nil
 
-		case "enter":
+		case m.matches(msg, m.keys.Select):
 			if m.focusedField == fieldEmoji {
 				// Open emoji modal
-				m.showEmojiModal = true
-				m.emojiSearch.Focus()
-				if m.selectedEmoji == "" {
-					m.emojiIndex = -1
-				} else {
-					m.emojiIndex = 0
-				}
-				return *m, textinput.Blink
+				return *m, m.emojiPicker.Open()
 			} else if m.focusedField == fieldCategory {
 				// Open category modal
 				if len(m.categories) > 0 {
@@ -268,63 +185,44 @@ func (m *FormModel) Update(msg tea.Msg) (FormModel, tea.Cmd) {
 			}
 			return *m, nil
 
-		case " ":
+		case m.matches(msg, m.keys.Toggle) && m.focusedField == fieldEmoji:
 			// Space on emoji field opens modal
-			if m.focusedField == fieldEmoji {
-				m.showEmojiModal = true
-				m.emojiSearch.Focus()
-				if m.selectedEmoji == "" {
-					m.emojiIndex = -1
-				} else {
-					m.emojiIndex = 0
-				}
-				return *m, textinput.Blink
-			}
+			return *m, m.emojiPicker.Open()
+
+		case m.matches(msg, m.keys.Toggle) && m.focusedField == fieldCategory && len(m.categories) > 0:
 			// Space on category field opens modal
-			if m.focusedField == fieldCategory && len(m.categories) > 0 {
-				m.showCategoryModal = true
-				m.categoryModalIndex = m.categoryIndex
-				return *m, nil
-			}
-			// Don't consume space for text inputs
-			// Fall through
+			m.showCategoryModal = true
+			m.categoryModalIndex = m.categoryIndex
+			return *m, nil
 
-		case "tab", "down":
+		case m.matches(msg, m.keys.NextField):
 			m.nextField()
 			return *m, nil
 
-		case "shift+tab", "up":
+		case m.matches(msg, m.keys.PrevField):
 			m.prevField()
 			return *m, nil
 
-		case "left":
-			if m.focusedField == fieldFrequency {
-				m.prevFrequency()
-				return *m, nil
-			}
-			// Fall through to let text inputs handle left arrow
+		case m.matches(msg, m.keys.Left) && m.focusedField == fieldFrequency:
+			m.prevFrequency()
+			return *m, nil
 
-		case "right":
-			if m.focusedField == fieldFrequency {
-				m.nextFrequency()
-				return *m, nil
-			}
-			// Fall through to let text inputs handle right arrow
+		case m.matches(msg, m.keys.Right) && m.focusedField == fieldFrequency:
+			m.nextFrequency()
+			return *m, nil
 
-		case "backspace", "delete":
+		case m.matches(msg, m.keys.Clear) && m.focusedField == fieldEmoji && m.emojiPicker.Value() != "":
 			// Clear emoji if on emoji field
-			if m.focusedField == fieldEmoji && m.selectedEmoji != "" {
-				m.selectedEmoji = ""
-				return *m, nil
-			}
+			m.emojiPicker.Clear()
+			return *m, nil
+
+		case m.matches(msg, m.keys.Clear) && m.focusedField == fieldCategory && m.categoryIndex >= 0:
 			// Clear category if on category field
-			if m.focusedField == fieldCategory && m.categoryIndex >= 0 {
-				m.categoryIndex = -1
-				return *m, nil
-			}
+			m.categoryIndex = -1
+			return *m, nil
 
-		case "ctrl+s":
-			// Ctrl+S to save from anywhere
+		case m.matches(msg, m.keys.Save):
+			// Save from anywhere
 			if m.nameInput.Value() != "" {
 				m.submitted = true
 				return *m, nil
@@ -348,6 +246,16 @@ func (m *FormModel) Update(msg tea.Msg) (FormModel, tea.Cmd) {
 	return *m, cmd
 }
 
+// matches checks a binding against the focused field, letting text inputs
+// keep printable keys
+func (m *FormModel) matches(msg tea.KeyMsg, b key.Binding) bool {
+	switch m.focusedField {
+	case fieldName, fieldDescription, fieldFrequencyValue, fieldTargetPerDay:
+		return ui.MatchesNonText(msg, b)
+	}
+	return key.Matches(msg, b)
+}
+
 func (m *FormModel) nextField() {
 	m.nameInput.Blur()
 	m.descInput.Blur()
@@ -427,7 +335,7 @@ func (m *FormModel) prevFrequency() {
 func (m *FormModel) GetHabit() *model.Habit {
 	m.habit.Name = m.nameInput.Value()
 	m.habit.Description = m.descInput.Value()
-	m.habit.Emoji = m.selectedEmoji
+	m.habit.Emoji = m.emojiPicker.Value()
 	m.habit.FrequencyType = m.frequencyType
 
 	if m.frequencyType == model.FreqTimesPerWeek {
@@ -489,7 +397,7 @@ func (m *FormModel) ViewContent() string {
 	s += m.renderField("Description", m.descInput.View(), m.focusedField == fieldDescription)
 
 	// Emoji field
-	emojiDisplay := m.renderEmojiSelector(m.focusedField == fieldEmoji)
+	emojiDisplay := m.emojiPicker.Field(m.focusedField == fieldEmoji)
 	s += m.renderField("Emoji", emojiDisplay, m.focusedField == fieldEmoji)
 
 	// Frequency field
@@ -508,9 +416,9 @@ func (m *FormModel) ViewContent() string {
 	catDisplay := m.renderCategorySelector(m.focusedField == fieldCategory)
 	s += m.renderField("Category", catDisplay, m.focusedField == fieldCategory)
 
-	s += "\n" + ui.MutedText.Render("tab: navigate  enter/space: pick category  backspace: clear")
+	s += "\n" + ui.HelpLine(m.keys.NextField, m.keys.Select, m.keys.Clear)
 	s += "\n"
-	s += ui.MutedText.Render("ctrl+s: save  esc: cancel")
+	s += ui.HelpLine(m.keys.Save, m.keys.Back)
 
 	return s
 }
@@ -564,7 +472,7 @@ func (m *FormModel) renderCategorySelector(focused bool) string {
 			display = cat.Name
 		}
 		if !focused {
-			return display
+			return ui.CategoryStyle(lipgloss.NewStyle(), cat.Color).Render(display)
 		}
 	}
 
@@ -598,6 +506,7 @@ func (m *FormModel) renderCategoryModalBox() string {
 			if cat.Emoji != "" {
 				catText = cat.Emoji + " " + catText
 			}
+			catText = ui.CategoryStyle(lipgloss.NewStyle(), cat.Color).Render(catText)
 
 			if i == m.categoryModalIndex {
 				s += "[" + catText + "]" + "\n"
@@ -608,7 +517,7 @@ func (m *FormModel) renderCategoryModalBox() string {
 	}
 	s += "\n"
 
-	s += ui.MutedText.Render("↑↓: navigate  enter: select  esc: cancel")
+	s += ui.HelpLine(m.keys.Up, m.keys.Down, m.keys.Select, m.keys.Back)
 
 	// Add modal box styling with dark background
 	modalWidth := 40
@@ -619,5 +528,23 @@ func (m *FormModel) renderCategoryModalBox() string {
 		Padding(1, 2).
 		Width(modalWidth)
 
-	return modalStyle.Render(s)
+	return ui.FitEmojis(modalStyle.Render(s), 3)
+}
+
+// helpSections returns the key bindings for the form or its open picker
+func (m *FormModel) helpSections() []ui.HelpSection {
+	switch {
+	case m.emojiPicker.IsOpen():
+		return []ui.HelpSection{ui.EmojiPickerHelp(m.keys)}
+	case m.showCategoryModal:
+		return []ui.HelpSection{
+			{Title: "Category Picker", Bindings: []key.Binding{m.keys.Up, m.keys.Down, m.keys.Select, m.keys.Back}},
+		}
+	}
+	return []ui.HelpSection{
+		{Title: "Habit Form", Bindings: []key.Binding{
+			m.keys.NextField, m.keys.PrevField, m.keys.Left, m.keys.Right,
+			m.keys.Select, m.keys.Clear, m.keys.Save, m.keys.Back,
+		}},
+	}
 }
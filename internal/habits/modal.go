@@ -0,0 +1,46 @@
+package habits
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+)
+
+// scrollModal moves the open picker's selection by one row per wheel step
+func (m *FormModel) scrollModal(delta int) {
+	switch {
+	case m.emojiPicker.IsOpen():
+		m.emojiPicker.Scroll(delta)
+	case m.showCategoryModal:
+		next := m.categoryModalIndex + delta
+		if next >= -1 && next < len(m.categories) {
+			m.categoryModalIndex = next
+		}
+	}
+}
+
+// clickModal selects the picker entry under a click; clicking outside the box closes it
+func (m *FormModel) clickModal(msg ui.ModalClickMsg) tea.Cmd {
+	switch {
+	case m.emojiPicker.IsOpen():
+		return m.emojiPicker.Click(msg, m.keys)
+
+	case m.showCategoryModal:
+		width, height := lipgloss.Size(m.renderCategoryModalBox())
+		if msg.Row < 0 || msg.Col < 0 || msg.Row >= height || msg.Col >= width {
+			m.showCategoryModal = false
+			return nil
+		}
+		// Rows: title, margin, blank, (none), blank, categories...
+		row := msg.Row - ui.ModalContentRow
+		switch index := row - 5; {
+		case row == 3:
+			m.categoryIndex = -1
+			m.showCategoryModal = false
+		case index >= 0 && index < len(m.categories):
+			m.categoryIndex = index
+			m.showCategoryModal = false
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package habits
+
+import (
+	"context"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+)
+
+// OrderChangedMsg is sent when habits were moved or the sort mode changed
+type OrderChangedMsg struct {
+	Err error
+}
+
+// sortHabits orders the loaded habits by the current sort mode, using
+// today's status for streaks and due dates
+func (m *Model) sortHabits() {
+	for i := range m.habits {
+		m.habits[i].CurrentStreak = m.status[m.habits[i].ID].CurrentStreak
+	}
+	sort.SliceStable(m.habits, func(i, j int) bool {
+		a, b := &m.habits[i], &m.habits[j]
+		return model.HabitLess(m.sortMode, a, b, m.status[a.ID].IsDue, m.status[b.ID].IsDue)
+	})
+}
+
+// moveSelected swaps the selected habit with its neighbour in the shown
+// list (delta -1 for up, 1 for down) and stores the new manual order. When
+// grouped by category, habits only move within their category.
+func (m *Model) moveSelected(delta int) tea.Cmd {
+	if !m.sortMode.Manual() {
+		return nil
+	}
+	from, to := m.list.Cursor, m.list.Cursor+delta
+	if from < 0 || from >= len(m.shown) || to < 0 || to >= len(m.shown) {
+		return nil
+	}
+	a, b := m.shown[from], m.shown[to]
+	if m.sortMode == model.SortCategory && categoryID(a) != categoryID(b) {
+		return nil
+	}
+
+	ids := make([]int64, len(m.habits))
+	for i, h := range m.habits {
+		switch h.ID {
+		case a.ID:
+			ids[i] = b.ID
+		case b.ID:
+			ids[i] = a.ID
+		default:
+			ids[i] = h.ID
+		}
+	}
+
+	m.followID = a.ID
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		return OrderChangedMsg{Err: m.service.Reorder(ctx, ids)}
+	}
+}
+
+// cycleSort switches to the next sort mode and stores it
+func (m *Model) cycleSort() tea.Cmd {
+	mode := m.sortMode.Next()
+	if len(m.shown) > 0 {
+		m.followID = m.shown[m.list.Cursor].ID
+	}
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		return OrderChangedMsg{Err: m.settings.SetSortMode(ctx, mode)}
+	}
+}
+
+// followSelected moves the cursor to the habit being followed after a reload
+func (m *Model) followSelected() {
+	if m.followID == 0 {
+		return
+	}
+	for i, h := range m.shown {
+		if h.ID == m.followID {
+			m.list.Cursor = i
+			break
+		}
+	}
+	m.followID = 0
+}
+
+func categoryID(h model.Habit) int64 {
+	if h.CategoryID == nil {
+		return 0
+	}
+	return *h.CategoryID
+}
+
+// sortHint renders the current sort mode with the keys that change the order
+func (m Model) sortHint() string {
+	hint := "sort: " + string(m.sortMode)
+	if m.sortMode.Manual() && m.keys.MoveDown.Enabled() && m.keys.MoveUp.Enabled() {
+		hint += "  " + m.keys.MoveDown.Help().Key + "/" + m.keys.MoveUp.Help().Key + ": reorder"
+	}
+	if m.keys.Sort.Enabled() {
+		hint += "  " + m.keys.Sort.Help().Key + ": change"
+	}
+	return ui.MutedText.Render(hint)
+}
@@ -1,8 +1,8 @@
 package habits
 
 import (
+	"context"
 	"database/sql"
-	"time"
 
 	"github.com/vittolewerissa/hbt/internal/shared/db"
 	"github.com/vittolewerissa/hbt/internal/shared/model"
@@ -19,43 +19,57 @@ func NewRepository(database *db.DB) *Repository {
 }
 
 // List returns all non-archived habits
-func (r *Repository) List() ([]model.Habit, error) {
+func (r *Repository) List(ctx context.Context) ([]model.Habit, error) {
 	query := `
 		SELECT h.id, h.name, h.description, h.emoji, h.category_id, h.frequency_type,
-		       h.frequency_value, h.target_per_day, h.created_at, h.archived_at,
-		       c.id, c.name, c.color, c.emoji
+		       h.frequency_value, h.target_per_day, h.position, h.created_at, h.archived_at,
+		       c.id, c.name, c.color, c.emoji, c.position
 		FROM habits h
 		LEFT JOIN categories c ON h.category_id = c.id
 		WHERE h.archived_at IS NULL
-		ORDER BY c.name NULLS LAST, h.name
+		ORDER BY c.position NULLS LAST, c.name, h.position, h.id
 	`
-	return r.queryHabits(query)
+	return r.queryHabits(ctx, query)
 }
 
 // ListAll returns all habits including archived
-func (r *Repository) ListAll() ([]model.Habit, error) {
+func (r *Repository) ListAll(ctx context.Context) ([]model.Habit, error) {
 	query := `
 		SELECT h.id, h.name, h.description, h.emoji, h.category_id, h.frequency_type,
-		       h.frequency_value, h.target_per_day, h.created_at, h.archived_at,
-		       c.id, c.name, c.color, c.emoji
+		       h.frequency_value, h.target_per_day, h.position, h.created_at, h.archived_at,
+		       c.id, c.name, c.color, c.emoji, c.position
 		FROM habits h
 		LEFT JOIN categories c ON h.category_id = c.id
-		ORDER BY h.archived_at IS NULL DESC, h.name
+		ORDER BY h.archived_at IS NULL DESC, h.position, h.id
 	`
-	return r.queryHabits(query)
+	return r.queryHabits(ctx, query)
+}
+
+// ListArchived returns archived habits, most recently archived first
+func (r *Repository) ListArchived(ctx context.Context) ([]model.Habit, error) {
+	query := `
+		SELECT h.id, h.name, h.description, h.emoji, h.category_id, h.frequency_type,
+		       h.frequency_value, h.target_per_day, h.position, h.created_at, h.archived_at,
+		       c.id, c.name, c.color, c.emoji, c.position
+		FROM habits h
+		LEFT JOIN categories c ON h.category_id = c.id
+		WHERE h.archived_at IS NOT NULL
+		ORDER BY h.archived_at DESC, h.name
+	`
+	return r.queryHabits(ctx, query)
 }
 
 // GetByID returns a habit by ID
-func (r *Repository) GetByID(id int64) (*model.Habit, error) {
+func (r *Repository) GetByID(ctx context.Context, id int64) (*model.Habit, error) {
 	query := `
 		SELECT h.id, h.name, h.description, h.emoji, h.category_id, h.frequency_type,
-		       h.frequency_value, h.target_per_day, h.created_at, h.archived_at,
-		       c.id, c.name, c.color, c.emoji
+		       h.frequency_value, h.target_per_day, h.position, h.created_at, h.archived_at,
+		       c.id, c.name, c.color, c.emoji, c.position
 		FROM habits h
 		LEFT JOIN categories c ON h.category_id = c.id
 		WHERE h.id = ?
 	`
-	habits, err := r.queryHabits(query, id)
+	habits, err := r.queryHabits(ctx, query, id)
 	if err != nil {
 		return nil, err
 	}
@@ -66,58 +80,112 @@ func (r *Repository) GetByID(id int64) (*model.Habit, error) {
 }
 
 // Create creates a new habit
-func (r *Repository) Create(h *model.Habit) error {
+func (r *Repository) Create(ctx context.Context, h *model.Habit) error {
+	now := r.db.Clock.Now()
+
+	// New habits go to the end of the manual order
 	query := `
-		INSERT INTO habits (name, description, emoji, category_id, frequency_type, frequency_value, target_per_day)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO habits (name, description, emoji, category_id, frequency_type, frequency_value, target_per_day, position, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, (SELECT COALESCE(MAX(position), -1) + 1 FROM habits), ?)
 	`
-	result, err := r.db.Exec(query, h.Name, h.Description, h.Emoji, h.CategoryID, h.FrequencyType, h.FrequencyValue, h.TargetPerDay)
-	if err != nil {
-		return err
-	}
-	id, err := result.LastInsertId()
+	err := r.db.LogChange(ctx, db.EntityHabit, nil, func(tx *sql.Tx) (any, error) {
+		result, err := tx.ExecContext(ctx, query, h.Name, h.Description, h.Emoji, h.CategoryID, h.FrequencyType, h.FrequencyValue, h.TargetPerDay, db.Timestamp(now))
+		if err != nil {
+			return nil, err
+		}
+		h.ID, err = result.LastInsertId()
+		return h.ID, err
+	})
 	if err != nil {
 		return err
 	}
-	h.ID = id
-	h.CreatedAt = time.Now()
+	h.CreatedAt = now
 	return nil
 }
 
+// Recreate inserts a deleted habit again with its original ID, position
+// and timestamps
+func (r *Repository) Recreate(ctx context.Context, h *model.Habit) error {
+	query := `
+		INSERT INTO habits (id, name, description, emoji, category_id, frequency_type, frequency_value, target_per_day, position, created_at, archived_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	var archivedAt *string
+	if h.ArchivedAt != nil {
+		t := db.Timestamp(*h.ArchivedAt)
+		archivedAt = &t
+	}
+	return r.db.ExecLogged(ctx, db.EntityHabit, h.ID, query, h.ID, h.Name, h.Description, h.Emoji, h.CategoryID, h.FrequencyType, h.FrequencyValue, h.TargetPerDay, h.Position, db.Timestamp(h.CreatedAt), archivedAt)
+}
+
 // Update updates an existing habit
-func (r *Repository) Update(h *model.Habit) error {
+func (r *Repository) Update(ctx context.Context, h *model.Habit) error {
 	query := `
 		UPDATE habits
 		SET name = ?, description = ?, emoji = ?, category_id = ?, frequency_type = ?, frequency_value = ?, target_per_day = ?
 		WHERE id = ?
 	`
-	_, err := r.db.Exec(query, h.Name, h.Description, h.Emoji, h.CategoryID, h.FrequencyType, h.FrequencyValue, h.TargetPerDay, h.ID)
-	return err
+	return r.db.ExecLogged(ctx, db.EntityHabit, h.ID, query, h.Name, h.Description, h.Emoji, h.CategoryID, h.FrequencyType, h.FrequencyValue, h.TargetPerDay, h.ID)
 }
 
 // Archive archives a habit
-func (r *Repository) Archive(id int64) error {
-	query := `UPDATE habits SET archived_at = CURRENT_TIMESTAMP WHERE id = ?`
-	_, err := r.db.Exec(query, id)
-	return err
+func (r *Repository) Archive(ctx context.Context, id int64) error {
+	query := `UPDATE habits SET archived_at = ? WHERE id = ?`
+	return r.db.ExecLogged(ctx, db.EntityHabit, id, query, r.db.Now(), id)
 }
 
 // Unarchive unarchives a habit
-func (r *Repository) Unarchive(id int64) error {
+func (r *Repository) Unarchive(ctx context.Context, id int64) error {
 	query := `UPDATE habits SET archived_at = NULL WHERE id = ?`
-	_, err := r.db.Exec(query, id)
-	return err
+	return r.db.ExecLogged(ctx, db.EntityHabit, id, query, id)
+}
+
+// Delete permanently deletes a habit and its completions
+func (r *Repository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Don't rely on ON DELETE CASCADE: foreign keys are enabled per
+	// connection, and each completion's removal is logged
+	completions, err := db.QueryIDs(ctx, tx, `SELECT id FROM completions WHERE habit_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	for _, completionID := range completions {
+		if err := r.db.ExecChange(ctx, tx, db.EntityCompletion, completionID, `DELETE FROM completions WHERE id = ?`, completionID); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM habit_stats WHERE habit_id = ?`, id); err != nil {
+		return err
+	}
+	if err := r.db.ExecChange(ctx, tx, db.EntityHabit, id, `DELETE FROM habits WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// Delete permanently deletes a habit
-func (r *Repository) Delete(id int64) error {
-	query := `DELETE FROM habits WHERE id = ?`
-	_, err := r.db.Exec(query, id)
-	return err
+// SetOrder stores the manual order of habits, giving each ID in ids its index as position
+func (r *Repository) SetOrder(ctx context.Context, ids []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, id := range ids {
+		if err := r.db.ExecChange(ctx, tx, db.EntityHabit, id, `UPDATE habits SET position = ? WHERE id = ?`, i, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-func (r *Repository) queryHabits(query string, args ...interface{}) ([]model.Habit, error) {
-	rows, err := r.db.Query(query, args...)
+func (r *Repository) queryHabits(ctx context.Context, query string, args ...interface{}) ([]model.Habit, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -126,14 +194,14 @@ func (r *Repository) queryHabits(query string, args ...interface{}) ([]model.Hab
 	var habits []model.Habit
 	for rows.Next() {
 		var h model.Habit
-		var categoryID, catID sql.NullInt64
+		var categoryID, catID, catPosition sql.NullInt64
 		var catName, catColor, catEmoji sql.NullString
 		var archivedAt sql.NullTime
 
 		err := rows.Scan(
 			&h.ID, &h.Name, &h.Description, &h.Emoji, &categoryID, &h.FrequencyType,
-			&h.FrequencyValue, &h.TargetPerDay, &h.CreatedAt, &archivedAt,
-			&catID, &catName, &catColor, &catEmoji,
+			&h.FrequencyValue, &h.TargetPerDay, &h.Position, &h.CreatedAt, &archivedAt,
+			&catID, &catName, &catColor, &catEmoji, &catPosition,
 		)
 		if err != nil {
 			return nil, err
@@ -147,10 +215,11 @@ func (r *Repository) queryHabits(query string, args ...interface{}) ([]model.Hab
 		}
 		if catID.Valid {
 			h.Category = &model.Category{
-				ID:    catID.Int64,
-				Name:  catName.String,
-				Color: catColor.String,
-				Emoji: catEmoji.String,
+				ID:       catID.Int64,
+				Name:     catName.String,
+				Color:    catColor.String,
+				Emoji:    catEmoji.String,
+				Position: int(catPosition.Int64),
 			}
 		}
 
@@ -1,39 +1,99 @@
 package habits
 
 import (
+	"context"
+	"time"
+
 	"github.com/vittolewerissa/hbt/internal/shared/db"
 	"github.com/vittolewerissa/hbt/internal/shared/model"
+	"github.com/vittolewerissa/hbt/internal/today"
 )
 
 // Service handles habit business logic
 type Service struct {
-	repo *Repository
+	repo      *Repository
+	todayRepo *today.Repository
 }
 
 // NewService creates a new habit service
 func NewService(database *db.DB) *Service {
 	return &Service{
-		repo: NewRepository(database),
+		repo:      NewRepository(database),
+		todayRepo: today.NewRepository(database),
 	}
 }
 
+// LifetimeStats summarizes a habit's whole completion history
+type LifetimeStats struct {
+	Completions    int
+	DaysCompleted  int
+	FirstCompleted *time.Time
+	LastCompleted  *time.Time
+	BestStreak     int
+}
+
+// ArchivedHabit is an archived habit with its lifetime stats
+type ArchivedHabit struct {
+	model.Habit
+	Stats LifetimeStats
+}
+
 // List returns all active habits
-func (s *Service) List() ([]model.Habit, error) {
-	return s.repo.List()
+func (s *Service) List(ctx context.Context) ([]model.Habit, error) {
+	return s.repo.List(ctx)
 }
 
 // ListAll returns all habits including archived
-func (s *Service) ListAll() ([]model.Habit, error) {
-	return s.repo.ListAll()
+func (s *Service) ListAll(ctx context.Context) ([]model.Habit, error) {
+	return s.repo.ListAll(ctx)
+}
+
+// ListArchived returns archived habits with their lifetime stats
+func (s *Service) ListArchived(ctx context.Context) ([]ArchivedHabit, error) {
+	habits, err := s.repo.ListArchived(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, err := s.todayRepo.CachedStats(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	archived := make([]ArchivedHabit, len(habits))
+	for i, h := range habits {
+		archived[i] = ArchivedHabit{Habit: h, Stats: lifetimeStats(cached[h.ID])}
+	}
+	return archived, nil
+}
+
+// lifetimeStats converts a habit's cached stats
+func lifetimeStats(c today.CachedStats) LifetimeStats {
+	return LifetimeStats{
+		Completions:    c.Completions,
+		DaysCompleted:  c.DaysCompleted,
+		FirstCompleted: parseDate(c.FirstCompleted),
+		LastCompleted:  parseDate(c.LastCompleted),
+		BestStreak:     c.BestStreak,
+	}
+}
+
+// parseDate parses a completion date, or returns nil if it isn't one
+func parseDate(s string) *time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil
+	}
+	return &t
 }
 
 // Get returns a habit by ID
-func (s *Service) Get(id int64) (*model.Habit, error) {
-	return s.repo.GetByID(id)
+func (s *Service) Get(ctx context.Context, id int64) (*model.Habit, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
 // Create creates a new habit
-func (s *Service) Create(h *model.Habit) error {
+func (s *Service) Create(ctx context.Context, h *model.Habit) error {
 	// Set defaults
 	if h.FrequencyType == "" {
 		h.FrequencyType = model.FreqDaily
@@ -41,25 +101,36 @@ func (s *Service) Create(h *model.Habit) error {
 	if h.FrequencyValue == 0 {
 		h.FrequencyValue = 1
 	}
-	return s.repo.Create(h)
+	return s.repo.Create(ctx, h)
+}
+
+// Recreate puts back a habit removed by Delete, keeping its ID so
+// anything that refers to it still does
+func (s *Service) Recreate(ctx context.Context, h *model.Habit) error {
+	return s.repo.Recreate(ctx, h)
 }
 
 // Update updates an existing habit
-func (s *Service) Update(h *model.Habit) error {
-	return s.repo.Update(h)
+func (s *Service) Update(ctx context.Context, h *model.Habit) error {
+	return s.repo.Update(ctx, h)
+}
+
+// Reorder stores a new manual order for the given habits
+func (s *Service) Reorder(ctx context.Context, ids []int64) error {
+	return s.repo.SetOrder(ctx, ids)
 }
 
 // Archive archives a habit (soft delete)
-func (s *Service) Archive(id int64) error {
-	return s.repo.Archive(id)
+func (s *Service) Archive(ctx context.Context, id int64) error {
+	return s.repo.Archive(ctx, id)
 }
 
 // Unarchive restores an archived habit
-func (s *Service) Unarchive(id int64) error {
-	return s.repo.Unarchive(id)
+func (s *Service) Unarchive(ctx context.Context, id int64) error {
+	return s.repo.Unarchive(ctx, id)
 }
 
 // Delete permanently removes a habit
-func (s *Service) Delete(id int64) error {
-	return s.repo.Delete(id)
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
 }
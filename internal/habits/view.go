@@ -1,15 +1,21 @@
 package habits
 
 import (
+	"context"
 	"fmt"
+	"slices"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/vittolewerissa/hbt/internal/category"
+	"github.com/vittolewerissa/hbt/internal/history"
+	"github.com/vittolewerissa/hbt/internal/settings"
 	"github.com/vittolewerissa/hbt/internal/shared/db"
 	"github.com/vittolewerissa/hbt/internal/shared/model"
 	"github.com/vittolewerissa/hbt/internal/shared/ui"
+	"github.com/vittolewerissa/hbt/internal/today"
 )
 
 // View modes
@@ -19,29 +25,53 @@ const (
 	modeList viewMode = iota
 	modeForm
 	modeConfirmDelete
+	modeArchive
+	modeConfirmPurge
 )
 
 // Model is the habits tab model
 type Model struct {
-	service     *Service
-	catService  *category.Service
-	habits      []model.Habit
-	categories  []model.Category
-	cursor      int
-	mode        viewMode
-	form        *FormModel
-	width       int
-	height      int
-	keys        ui.KeyMap
-	err         error
+	service      *Service
+	catService   *category.Service
+	todayService *today.Service
+	settings     *settings.Service
+	loads        *ui.Loader
+	archiveLoads *ui.Loader
+	habits       []model.Habit
+	status       map[int64]today.HabitWithStatus
+	shown        []model.Habit // habits that pass the filter
+	categories   []model.Category
+	filterBar    ui.FilterBar
+	filter       model.HabitFilter
+	sortMode     model.SortMode
+	followID     int64 // habit to put the cursor on after the next load
+	list         ui.ScrollList
+	deleting     model.Habit // habit the delete dialog asks about
+	archived     []ArchivedHabit
+	archiveList  ui.ScrollList
+	purging      ArchivedHabit // archived habit the purge dialog asks about
+	purgeInput   textinput.Model
+	mode         viewMode
+	form         *FormModel
+	emojiPrefs   settings.EmojiPrefs
+	width        int
+	height       int
+	panelHeight  int
+	keys         ui.KeyMap
+	err          error
 }
 
 // New creates a new habits model
-func New(database *db.DB) Model {
+func New(database *db.DB, keys ui.KeyMap) Model {
 	return Model{
-		service:    NewService(database),
-		catService: category.NewService(database),
-		keys:       ui.DefaultKeyMap,
+		service:      NewService(database),
+		catService:   category.NewService(database),
+		todayService: today.NewService(database),
+		settings:     settings.NewService(database),
+		loads:        &ui.Loader{},
+		archiveLoads: &ui.Loader{},
+		filterBar:    ui.NewFilterBar(),
+		keys:         keys,
 	}
 }
 
@@ -50,48 +80,155 @@ func (m Model) Init() tea.Cmd {
 	return m.loadData
 }
 
+// Reload refreshes the habits, and the archived habits if they're being browsed
+func (m Model) Reload() tea.Cmd {
+	if m.mode == modeArchive || m.mode == modeConfirmPurge {
+		return tea.Batch(m.loadData, m.loadArchived)
+	}
+	return m.loadData
+}
+
 // HabitsLoadedMsg is sent when habits are loaded
 type HabitsLoadedMsg struct {
 	Habits     []model.Habit
 	Categories []model.Category
+	Status     map[int64]today.HabitWithStatus // today's status, for filter chips and sorting
+	SortMode   model.SortMode
+	EmojiPrefs settings.EmojiPrefs
 	Err        error
 }
 
 // HabitSavedMsg is sent when a habit is saved
 type HabitSavedMsg struct {
-	Habit *model.Habit
+	Habit  *model.Habit
+	Change *history.Change
+	Err    error
+}
+
+// EmojiPrefsSavedMsg is sent when the emoji picker's recents or skin tone
+// have been stored
+type EmojiPrefsSavedMsg struct {
+	Prefs settings.EmojiPrefs
 	Err   error
 }
 
 // HabitDeletedMsg is sent when a habit is deleted
 type HabitDeletedMsg struct {
-	Err error
+	Change *history.Change
+	Err    error
 }
 
 func (m Model) loadData() tea.Msg {
-	habits, err := m.service.List()
+	ctx, cancel := m.loads.Start()
+	defer cancel()
+	habits, err := m.service.List(ctx)
+	if err != nil {
+		return HabitsLoadedMsg{Err: err}
+	}
+	categories, err := m.catService.List(ctx)
 	if err != nil {
 		return HabitsLoadedMsg{Err: err}
 	}
-	categories, err := m.catService.List()
+	todays, err := m.todayService.GetHabitsForToday(ctx)
 	if err != nil {
 		return HabitsLoadedMsg{Err: err}
 	}
-	return HabitsLoadedMsg{Habits: habits, Categories: categories}
+	status := make(map[int64]today.HabitWithStatus, len(todays))
+	for _, h := range todays {
+		status[h.ID] = h
+	}
+	mode, err := m.settings.SortMode(ctx)
+	if err != nil {
+		return HabitsLoadedMsg{Err: err}
+	}
+	prefs, err := m.settings.EmojiPrefs(ctx)
+	if err != nil {
+		return HabitsLoadedMsg{Err: err}
+	}
+	return HabitsLoadedMsg{Habits: habits, Categories: categories, Status: status, SortMode: mode, EmojiPrefs: prefs}
+}
+
+// saveEmojiPrefs stores a change to the emoji picker's state and reads
+// back the result
+func (m Model) saveEmojiPrefs(save func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		if err := save(ctx); err != nil {
+			return EmojiPrefsSavedMsg{Err: err}
+		}
+		prefs, err := m.settings.EmojiPrefs(ctx)
+		return EmojiPrefsSavedMsg{Prefs: prefs, Err: err}
+	}
 }
 
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case HabitsLoadedMsg:
+		if ui.Superseded(msg.Err) {
+			return m, nil
+		}
 		if msg.Err != nil {
 			m.err = msg.Err
 			return m, nil
 		}
+		m.err = nil
 		m.habits = msg.Habits
 		m.categories = msg.Categories
+		m.status = msg.Status
+		m.sortMode = msg.SortMode
+		m.emojiPrefs = msg.EmojiPrefs
+		m.sortHabits()
+		m.applyFilter()
+		m.followSelected()
+		m.syncScroll()
+		// Close the dialog if its habit was deleted elsewhere
+		gone := !slices.ContainsFunc(m.habits, func(h model.Habit) bool { return h.ID == m.deleting.ID })
+		if m.mode == modeConfirmDelete && gone {
+			m.mode = modeList
+		}
 		return m, nil
 
+	case ArchivedLoadedMsg:
+		if ui.Superseded(msg.Err) {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.archived = msg.Habits
+		m.archiveList.Clamp(len(m.archived))
+		// Close the dialog if its habit was restored or purged elsewhere
+		gone := !slices.ContainsFunc(m.archived, func(h ArchivedHabit) bool { return h.ID == m.purging.ID })
+		if m.mode == modeConfirmPurge && gone {
+			m.mode = modeArchive
+		}
+		m.syncScroll()
+		return m, nil
+
+	case HabitRestoredMsg, HabitPurgedMsg:
+		var err error
+		switch msg := msg.(type) {
+		case HabitRestoredMsg:
+			err = msg.Err
+		case HabitPurgedMsg:
+			err = msg.Err
+		}
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		return m, tea.Batch(m.loadArchived, m.loadData)
+
+	case OrderChangedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		return m, m.loadData
+
 	case HabitSavedMsg:
 		if msg.Err != nil {
 			m.err = msg.Err
@@ -101,14 +238,32 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.form = nil
 		return m, m.loadData
 
+	case EmojiPrefsSavedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.emojiPrefs = msg.Prefs
+		return m, nil
+
+	case ui.EmojiPickedMsg:
+		return m, m.saveEmojiPrefs(func(ctx context.Context) error {
+			return m.settings.UseEmoji(ctx, msg.Emoji)
+		})
+
+	case ui.SkinToneChangedMsg:
+		return m, m.saveEmojiPrefs(func(ctx context.Context) error {
+			return m.settings.SetSkinTone(ctx, msg.Tone)
+		})
+
 	case HabitDeletedMsg:
 		if msg.Err != nil {
 			m.err = msg.Err
 			return m, nil
 		}
 		m.mode = modeList
-		if m.cursor >= len(m.habits)-1 && m.cursor > 0 {
-			m.cursor--
+		if m.list.Cursor >= len(m.shown)-1 && m.list.Cursor > 0 {
+			m.list.Cursor--
 		}
 		return m, m.loadData
 
@@ -136,36 +291,103 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, cmd
 		}
 		return m.handleKey(msg)
+
+	case ui.ClickMsg:
+		if m.mode == modeList && m.err == nil {
+			_, items := m.listRows()
+			line := msg.Row - lipgloss.Height(m.filterBar.View(m.filter.Chips(), 0, 0)) + 1
+			row := m.list.RowAt(line, len(items))
+			if row >= 0 && items[row] >= 0 {
+				m.list.Cursor = items[row]
+				m.syncScroll()
+			}
+		}
+		if m.mode == modeArchive && m.err == nil {
+			if i := m.archivedAt(msg.Row); i >= 0 {
+				m.archiveList.Cursor = i
+				m.syncScroll()
+			}
+		}
+
+	case ui.ScrollMsg:
+		switch m.mode {
+		case modeList:
+			m.list.Move(msg.Delta, len(m.shown))
+			m.syncScroll()
+		case modeArchive:
+			m.archiveList.Move(msg.Delta, len(m.archived))
+			m.syncScroll()
+		case modeForm:
+			if m.form != nil {
+				*m.form, _ = m.form.Update(msg)
+			}
+		}
+
+	case ui.ModalClickMsg:
+		if m.mode == modeForm && m.form != nil {
+			var cmd tea.Cmd
+			*m.form, cmd = m.form.Update(msg)
+			return m, cmd
+		}
 	}
 
 	return m, nil
 }
 
+// openForm shows the form for adding a habit, or editing habit if it's not nil
+func (m *Model) openForm(habit *model.Habit) tea.Cmd {
+	m.form = NewForm(habit, m.categories, m.keys, m.width, m.height)
+	m.form.emojiPicker.SetPrefs(m.emojiPrefs.Recents.Emojis(), m.emojiPrefs.Tone)
+	m.mode = modeForm
+	return m.form.Init()
+}
+
 func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch m.mode {
 	case modeList:
-		switch {
-		case key.Matches(msg, m.keys.Up):
-			if m.cursor > 0 {
-				m.cursor--
+		if m.filterBar.Editing() {
+			changed, cmd := m.filterBar.Update(msg, m.keys)
+			if changed {
+				m.list.Cursor = 0
 			}
-		case key.Matches(msg, m.keys.Down):
-			if m.cursor < len(m.habits)-1 {
-				m.cursor++
+			m.applyFilter()
+			return m, cmd
+		}
+
+		if m.list.HandleKey(msg, m.keys, len(m.shown)) {
+			m.syncScroll()
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Filter):
+			cmd := m.filterBar.Open()
+			m.syncScroll()
+			return m, cmd
+		case key.Matches(msg, m.keys.Back):
+			if m.filterBar.Query() != "" {
+				m.SetFilter("")
 			}
+		case key.Matches(msg, m.keys.MoveUp):
+			return m, m.moveSelected(-1)
+		case key.Matches(msg, m.keys.MoveDown):
+			return m, m.moveSelected(1)
+		case key.Matches(msg, m.keys.Sort):
+			return m, m.cycleSort()
+		case key.Matches(msg, m.keys.Archive):
+			m.mode = modeArchive
+			m.syncScroll()
+			return m, m.loadArchived
 		case key.Matches(msg, m.keys.Add):
-			m.form = NewForm(nil, m.categories, m.width, m.height)
-			m.mode = modeForm
-			return m, m.form.Init()
+			return m, m.openForm(nil)
 		case key.Matches(msg, m.keys.Edit):
-			if len(m.habits) > 0 {
-				habit := m.habits[m.cursor]
-				m.form = NewForm(&habit, m.categories, m.width, m.height)
-				m.mode = modeForm
-				return m, m.form.Init()
+			if len(m.shown) > 0 {
+				habit := m.shown[m.list.Cursor]
+				return m, m.openForm(&habit)
 			}
 		case key.Matches(msg, m.keys.Delete):
-			if len(m.habits) > 0 {
+			if len(m.shown) > 0 {
+				m.deleting = m.shown[m.list.Cursor]
 				m.mode = modeConfirmDelete
 			}
 		}
@@ -180,12 +402,16 @@ func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 			}
 		}
 
+	case modeArchive:
+		return m.handleArchiveKey(msg)
+
+	case modeConfirmPurge:
+		return m.handlePurgeKey(msg)
+
 	case modeConfirmDelete:
 		switch {
 		case key.Matches(msg, m.keys.Confirm):
-			if len(m.habits) > 0 {
-				return m, m.deleteHabit(m.habits[m.cursor].ID)
-			}
+			return m, m.deleteHabit(m.deleting)
 		case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Back):
 			m.mode = modeList
 		}
@@ -194,29 +420,129 @@ func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// FilterQuery returns the active filter query
+func (m Model) FilterQuery() string {
+	return m.filterBar.Query()
+}
+
+// SetFilter replaces the filter query, e.g. when carrying it over from the today tab
+func (m *Model) SetFilter(query string) {
+	if query != m.filterBar.Query() {
+		m.filterBar.SetQuery(query)
+		m.list.Cursor = 0
+	}
+	m.applyFilter()
+}
+
+// applyFilter recomputes which habits are shown
+func (m *Model) applyFilter() {
+	m.filter = model.ParseHabitFilter(m.filterBar.Query())
+	m.shown = m.shown[:0]
+	for i := range m.habits {
+		h := &m.habits[i]
+		st, ok := m.status[h.ID]
+		if m.filter.Matches(h, ok && st.IsDue, ok && st.CompletedToday) {
+			m.shown = append(m.shown, *h)
+		}
+	}
+	m.list.Clamp(len(m.shown))
+	m.syncScroll()
+}
+
+// SetHeight sets the number of rows available for the tab's panel content
+func (m *Model) SetHeight(height int) {
+	m.panelHeight = height
+	m.syncScroll()
+}
+
+// syncScroll sizes the list to the panel, leaving room for the key hints,
+// and keeps the selected habit (and its category header) in view
+func (m *Model) syncScroll() {
+	m.syncArchiveScroll()
+
+	m.list.Height = 0
+	if m.panelHeight > 0 {
+		filterHeight := lipgloss.Height(m.filterBar.View(m.filter.Chips(), 0, 0)) - 1
+		m.list.Height = max(1, m.panelHeight-3-filterHeight)
+	}
+
+	_, items := m.listRows()
+	for row, item := range items {
+		if item == m.list.Cursor {
+			first := row
+			for first > 0 && items[first-1] == -1 {
+				first--
+			}
+			m.list.Follow(first, row, len(items))
+			return
+		}
+	}
+}
+
 func (m Model) saveHabit(h *model.Habit) tea.Cmd {
+	if h.ID == 0 {
+		return m.createHabit(h)
+	}
 	return func() tea.Msg {
-		var err error
-		if h.ID == 0 {
-			err = m.service.Create(h)
-		} else {
-			err = m.service.Update(h)
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		before, err := m.service.Get(ctx, h.ID)
+		if err == nil {
+			err = m.service.Update(ctx, h)
+		}
+		if err != nil {
+			return HabitSavedMsg{Habit: h, Err: err}
 		}
-		return HabitSavedMsg{Habit: h, Err: err}
+		after := *h
+		return HabitSavedMsg{Habit: h, Change: &history.Change{
+			Label: fmt.Sprintf("Updated '%s'", h.Name),
+			Undo:  func(ctx context.Context) error { return m.service.Update(ctx, before) },
+			Redo:  func(ctx context.Context) error { return m.service.Update(ctx, &after) },
+		}}
 	}
 }
 
-func (m Model) deleteHabit(id int64) tea.Cmd {
+// createHabit adds a habit. Undoing removes it; redoing puts it back with
+// the same ID.
+func (m Model) createHabit(h *model.Habit) tea.Cmd {
 	return func() tea.Msg {
-		err := m.service.Archive(id)
-		return HabitDeletedMsg{Err: err}
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		err := m.service.Create(ctx, h)
+		var created *model.Habit
+		if err == nil {
+			created, err = m.service.Get(ctx, h.ID)
+		}
+		if err != nil {
+			return HabitSavedMsg{Habit: h, Err: err}
+		}
+		return HabitSavedMsg{Habit: h, Change: &history.Change{
+			Label: fmt.Sprintf("Created '%s'", h.Name),
+			Undo:  func(ctx context.Context) error { return m.service.Delete(ctx, created.ID) },
+			Redo:  func(ctx context.Context) error { return m.service.Recreate(ctx, created) },
+		}}
+	}
+}
+
+func (m Model) deleteHabit(h model.Habit) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		if err := m.service.Archive(ctx, h.ID); err != nil {
+			return HabitDeletedMsg{Err: err}
+		}
+		return HabitDeletedMsg{Change: &history.Change{
+			Label: fmt.Sprintf("Archived '%s'", h.Name),
+			Undo:  func(ctx context.Context) error { return m.service.Unarchive(ctx, h.ID) },
+			Redo:  func(ctx context.Context) error { return m.service.Archive(ctx, h.ID) },
+		}}
 	}
 }
 
 // View renders the habits tab (with title)
 func (m Model) View() string {
 	if m.err != nil {
-		return ui.MutedText.Render(fmt.Sprintf("Error: %v", m.err))
+		return ui.MutedText.Render(fmt.Sprintf("Error: %v", db.Explain(m.err)))
 	}
 
 	switch m.mode {
@@ -234,7 +560,7 @@ func (m Model) View() string {
 // ViewContent renders just the content without title (for titled panels)
 func (m Model) ViewContent() string {
 	if m.err != nil {
-		return ui.MutedText.Render(fmt.Sprintf("Error: %v", m.err))
+		return ui.MutedText.Render(fmt.Sprintf("Error: %v", db.Explain(m.err)))
 	}
 
 	switch m.mode {
@@ -244,6 +570,10 @@ func (m Model) ViewContent() string {
 		}
 	case modeConfirmDelete:
 		return m.renderConfirmDeleteContent()
+	case modeArchive:
+		return m.renderArchiveContent()
+	case modeConfirmPurge:
+		return m.renderPurgeContent()
 	}
 
 	return m.renderListContent()
@@ -264,6 +594,38 @@ func (m Model) renderListContent() string {
 		return s
 	}
 
+	s += m.filterBar.View(m.filter.Chips(), len(m.shown), len(m.habits))
+
+	if len(m.shown) == 0 {
+		s += ui.MutedText.Render("No habits match the filter.")
+		return s
+	}
+
+	rows, _ := m.listRows()
+	s += m.list.Render(rows)
+
+	s += "\n" + ui.HelpLine(m.keys.Add, m.keys.Edit, m.keys.Delete, m.keys.Filter, m.keys.Archive)
+	s += "\n" + m.sortHint()
+
+	return s
+}
+
+// listRows renders the grouped habit list one line per row. For each row,
+// items holds the index of the habit drawn on it, or -1 for headers.
+func (m Model) listRows() (rows []string, items []int) {
+	add := func(row string, item int) {
+		rows = append(rows, row)
+		items = append(items, item)
+	}
+
+	// Other sort modes list habits flat
+	if m.sortMode != model.SortCategory {
+		for i, habit := range m.shown {
+			add(m.renderHabitLine(habit, i), i)
+		}
+		return rows, items
+	}
+
 	// Group habits by category
 	type categoryGroup struct {
 		category *model.Category
@@ -273,7 +635,7 @@ func (m Model) renderListContent() string {
 	categoryMap := make(map[int64]*categoryGroup)
 	var uncategorized []model.Habit
 
-	for _, habit := range m.habits {
+	for _, habit := range m.shown {
 		if habit.Category != nil {
 			catID := habit.Category.ID
 			if categoryMap[catID] == nil {
@@ -295,7 +657,7 @@ func (m Model) renderListContent() string {
 		if group, exists := categoryMap[cat.ID]; exists {
 			// Add horizontal separator before category (except first)
 			if !firstCategory {
-				s += ui.MutedText.Render("────────────────────────────────") + "\n"
+				add(ui.MutedText.Render("────────────────────────────────"), -1)
 			}
 			firstCategory = false
 
@@ -305,15 +667,13 @@ func (m Model) renderListContent() string {
 				emoji = "📁"
 			}
 
-			titleStyle := lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color(cat.Color))
+			titleStyle := ui.CategoryStyle(lipgloss.NewStyle().Bold(true), cat.Color)
 
-			s += titleStyle.Render(cat.Name + " " + emoji) + "\n"
+			add(titleStyle.Render(cat.Name+" "+emoji), -1)
 
 			// Build habits list for this category
 			for _, habit := range group.habits {
-				s += m.renderHabitLine(habit, currentIndex) + "\n"
+				add(m.renderHabitLine(habit, currentIndex), currentIndex)
 				currentIndex++
 			}
 		}
@@ -323,24 +683,22 @@ func (m Model) renderListContent() string {
 	if len(uncategorized) > 0 {
 		// Add horizontal separator if there were categories before
 		if !firstCategory {
-			s += ui.MutedText.Render("────────────────────────────────") + "\n"
+			add(ui.MutedText.Render("────────────────────────────────"), -1)
 		}
 
-		s += ui.MutedText.Render("Uncategorized") + "\n"
+		add(ui.MutedText.Render("Uncategorized"), -1)
 		for _, habit := range uncategorized {
-			s += m.renderHabitLine(habit, currentIndex) + "\n"
+			add(m.renderHabitLine(habit, currentIndex), currentIndex)
 			currentIndex++
 		}
 	}
 
-	s += "\n" + ui.MutedText.Render("a: add  e: edit  d: delete")
-
-	return s
+	return rows, items
 }
 
 func (m Model) renderHabitLine(habit model.Habit, index int) string {
 	cursor := "  "
-	if index == m.cursor {
+	if index == m.list.Cursor {
 		cursor = "> "
 	}
 
@@ -351,8 +709,10 @@ func (m Model) renderHabitLine(habit model.Habit, index int) string {
 	}
 
 	name := habit.Name
-	if index == m.cursor {
+	if index == m.list.Cursor {
 		name = ui.SelectedItem.Render(name)
+	} else if habit.Category != nil {
+		name = ui.CategoryStyle(ui.NormalItem, habit.Category.Color).Render(name)
 	} else {
 		name = ui.NormalItem.Render(name)
 	}
@@ -386,23 +746,23 @@ func (m Model) renderConfirmDelete() string {
 }
 
 func (m Model) renderConfirmDeleteContent() string {
-	habit := m.habits[m.cursor]
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
-		fmt.Sprintf("Are you sure you want to delete '%s'?", habit.Name),
+		fmt.Sprintf("Are you sure you want to delete '%s'?", m.deleting.Name),
+		ui.MutedText.Render("It's archived and can be restored from "+m.keys.Archive.Help().Key+"."),
 		"",
-		ui.MutedText.Render("y: confirm  n: cancel"),
+		ui.HelpLine(m.keys.Confirm, m.keys.Cancel),
 	)
 }
 
 // Focused returns whether this view should receive key events
 func (m Model) Focused() bool {
-	return m.mode == modeForm
+	return m.mode == modeForm || m.mode == modeConfirmPurge || m.filterBar.Editing()
 }
 
 // HasModal returns true if showing a modal dialog
 func (m Model) HasModal() bool {
-	return m.mode == modeForm && m.form != nil && (m.form.showCategoryModal || m.form.showEmojiModal)
+	return m.mode == modeForm && m.form != nil && (m.form.showCategoryModal || m.form.emojiPicker.IsOpen())
 }
 
 // RenderModalContent renders just the modal box content for overlay
@@ -410,8 +770,33 @@ func (m Model) RenderModalContent() string {
 	if !m.HasModal() {
 		return ""
 	}
-	if m.form.showEmojiModal {
-		return m.form.renderEmojiModalBox()
+	if m.form.emojiPicker.IsOpen() {
+		return m.form.emojiPicker.View(m.keys)
 	}
 	return m.form.renderCategoryModalBox()
 }
+
+// HelpSections returns the key bindings relevant to the current mode
+func (m Model) HelpSections() []ui.HelpSection {
+	switch m.mode {
+	case modeForm:
+		if m.form != nil {
+			return m.form.helpSections()
+		}
+	case modeArchive, modeConfirmPurge:
+		return m.archiveHelpSections()
+	case modeConfirmDelete:
+		return []ui.HelpSection{
+			{Title: "Delete Habit", Bindings: []key.Binding{m.keys.Confirm, m.keys.Cancel, m.keys.Back}},
+		}
+	}
+	if m.filterBar.Editing() {
+		return []ui.HelpSection{{Title: "Filter", Bindings: ui.FilterHelp(m.keys)}}
+	}
+	return []ui.HelpSection{
+		{Title: "Habits", Bindings: []key.Binding{
+			m.keys.Up, m.keys.Down, m.keys.Add, m.keys.Edit, m.keys.Delete, m.keys.Filter,
+			m.keys.MoveUp, m.keys.MoveDown, m.keys.Sort, m.keys.Archive,
+		}},
+	}
+}
@@ -0,0 +1,64 @@
+package history
+
+import "context"
+
+// maxChanges is how many changes are kept for undo
+const maxChanges = 100
+
+// Change is a mutation that can be reversed and applied again
+type Change struct {
+	Label string // what was done, e.g. "Archived 'Read'"
+	Undo  func(ctx context.Context) error
+	Redo  func(ctx context.Context) error
+}
+
+// History holds the changes made this session, most recent last
+type History struct {
+	done   []Change
+	undone []Change
+}
+
+// Record adds a change that was just made. Anything that was undone can no
+// longer be redone.
+func (h *History) Record(c Change) {
+	h.done = append(h.done, c)
+	if len(h.done) > maxChanges {
+		h.done = h.done[len(h.done)-maxChanges:]
+	}
+	h.undone = nil
+}
+
+// Undo takes the most recent change, moving it to the redo stack. The
+// caller runs its Undo func.
+func (h *History) Undo() (Change, bool) {
+	if len(h.done) == 0 {
+		return Change{}, false
+	}
+	c := h.done[len(h.done)-1]
+	h.done = h.done[:len(h.done)-1]
+	h.undone = append(h.undone, c)
+	return c, true
+}
+
+// Redo takes the most recently undone change, moving it back to the undo
+// stack. The caller runs its Redo func.
+func (h *History) Redo() (Change, bool) {
+	if len(h.undone) == 0 {
+		return Change{}, false
+	}
+	c := h.undone[len(h.undone)-1]
+	h.undone = h.undone[:len(h.undone)-1]
+	h.done = append(h.done, c)
+	return c, true
+}
+
+// Drop forgets the change last returned by Undo (redo is false) or Redo
+// (redo is true), for when running it failed and the data no longer
+// matches either side of it
+func (h *History) Drop(redo bool) {
+	if redo && len(h.done) > 0 {
+		h.done = h.done[:len(h.done)-1]
+	} else if !redo && len(h.undone) > 0 {
+		h.undone = h.undone[:len(h.undone)-1]
+	}
+}
@@ -1,7 +1,14 @@
 package settings
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
 	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/emoji"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
 )
 
 // Service handles settings management
@@ -15,24 +22,74 @@ func NewService(database *db.DB) *Service {
 }
 
 // Get retrieves a setting value
-func (s *Service) Get(key string) (string, error) {
+func (s *Service) Get(ctx context.Context, key string) (string, error) {
 	var value string
-	err := s.db.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&value)
+	err := s.db.QueryRowContext(ctx, "SELECT value FROM settings WHERE key = ?", key).Scan(&value)
 	return value, err
 }
 
 // Set stores a setting value
-func (s *Service) Set(key, value string) error {
-	_, err := s.db.Exec(
+func (s *Service) Set(ctx context.Context, key, value string) error {
+	return s.db.ExecLogged(ctx, db.EntitySetting, key,
 		"INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)",
 		key, value,
 	)
-	return err
+}
+
+// SortMode returns the configured habit sort order
+func (s *Service) SortMode(ctx context.Context) (model.SortMode, error) {
+	value, err := s.Get(ctx, KeySortMode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ParseSortMode(Defaults[KeySortMode]), nil
+	}
+	if err != nil {
+		return model.SortCategory, err
+	}
+	return model.ParseSortMode(value), nil
+}
+
+// SetSortMode stores the habit sort order
+func (s *Service) SetSortMode(ctx context.Context, mode model.SortMode) error {
+	return s.Set(ctx, KeySortMode, string(mode))
+}
+
+// EmojiPrefs are the emoji picker's remembered state
+type EmojiPrefs struct {
+	Recents emoji.Recents
+	Tone    emoji.Tone
+}
+
+// EmojiPrefs returns the recently used emojis and chosen skin tone
+func (s *Service) EmojiPrefs(ctx context.Context) (EmojiPrefs, error) {
+	var prefs EmojiPrefs
+	recents, err := s.Get(ctx, KeyRecentEmojis)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return prefs, err
+	}
+	tone, err := s.Get(ctx, KeySkinTone)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return prefs, err
+	}
+	return EmojiPrefs{Recents: emoji.ParseRecents(recents), Tone: emoji.ParseTone(tone)}, nil
+}
+
+// UseEmoji records that e was picked, moving it up the recent emojis
+func (s *Service) UseEmoji(ctx context.Context, e string) error {
+	prefs, err := s.EmojiPrefs(ctx)
+	if err != nil {
+		return err
+	}
+	return s.Set(ctx, KeyRecentEmojis, prefs.Recents.Use(e).String())
+}
+
+// SetSkinTone stores the skin tone the emoji picker shows
+func (s *Service) SetSkinTone(ctx context.Context, tone emoji.Tone) error {
+	return s.Set(ctx, KeySkinTone, strconv.Itoa(int(tone)))
 }
 
 // GetAll retrieves all settings
-func (s *Service) GetAll() (map[string]string, error) {
-	rows, err := s.db.Query("SELECT key, value FROM settings")
+func (s *Service) GetAll(ctx context.Context) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT key, value FROM settings")
 	if err != nil {
 		return nil, err
 	}
@@ -54,10 +111,17 @@ const (
 	KeyDatabasePath = "database_path"
 	KeyTheme        = "theme"
 	KeyWeekStart    = "week_start" // 0=Sunday, 1=Monday
+	KeySortMode     = "sort_mode"  // manual, name, streak, due-first or category
+
+	KeyCollapsedSections = "collapsed_sections" // comma-separated category IDs, 0=Uncategorized
+	KeyCheckInSkipped    = "checkin_skipped"    // date the daily check-in was last skipped
+	KeyRecentEmojis      = "recent_emojis"      // comma-separated emoji:weight pairs, most weighted first
+	KeySkinTone          = "skin_tone"          // 0=default, 1=light to 5=dark
 )
 
 // Defaults
 var Defaults = map[string]string{
 	KeyTheme:     "default",
 	KeyWeekStart: "1", // Monday
+	KeySortMode:  string(model.SortCategory),
 }
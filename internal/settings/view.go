@@ -1,6 +1,7 @@
 package settings
 
 import (
+	"context"
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -21,12 +22,12 @@ type Model struct {
 }
 
 // New creates a new settings model
-func New(database *db.DB, dbPath string) Model {
+func New(database *db.DB, dbPath string, keys ui.KeyMap) Model {
 	return Model{
 		service:  NewService(database),
 		dbPath:   dbPath,
 		settings: make(map[string]string),
-		keys:     ui.DefaultKeyMap,
+		keys:     keys,
 	}
 }
 
@@ -42,7 +43,9 @@ type SettingsLoadedMsg struct {
 }
 
 func (m Model) loadSettings() tea.Msg {
-	settings, err := m.service.GetAll()
+	ctx, cancel := db.WithTimeout(context.Background())
+	defer cancel()
+	settings, err := m.service.GetAll(ctx)
 	return SettingsLoadedMsg{Settings: settings, Err: err}
 }
 
@@ -68,7 +71,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 // View renders the settings tab
 func (m Model) View() string {
 	if m.err != nil {
-		return ui.MutedText.Render(fmt.Sprintf("Error: %v", m.err))
+		return ui.MutedText.Render(fmt.Sprintf("Error: %v", db.Explain(m.err)))
 	}
 
 	var s string
@@ -88,16 +91,17 @@ func (m Model) View() string {
 
 	s += infoStyle.Render(info) + "\n\n"
 
-	// Keyboard shortcuts
-	s += lipgloss.NewStyle().Bold(true).Render("Keyboard Shortcuts") + "\n\n"
-	s += ui.MutedText.Render("  Tab/Shift+Tab  ") + "Switch tabs\n"
-	s += ui.MutedText.Render("  j/k or ↑/↓     ") + "Navigate list\n"
-	s += ui.MutedText.Render("  Space/Enter    ") + "Toggle/Select\n"
-	s += ui.MutedText.Render("  a              ") + "Add new habit\n"
-	s += ui.MutedText.Render("  e              ") + "Edit selected\n"
-	s += ui.MutedText.Render("  d              ") + "Delete selected\n"
-	s += ui.MutedText.Render("  ?              ") + "Toggle help\n"
-	s += ui.MutedText.Render("  q              ") + "Quit\n"
+	// Keyboard shortcuts (generated from the effective key map)
+	s += lipgloss.NewStyle().Bold(true).Render("Keyboard Shortcuts") + "\n"
+	s += ui.MutedText.Render("Customize in "+ui.KeyMapPath()) + "\n\n"
+	for _, group := range m.keys.FullHelp() {
+		for _, b := range group {
+			if !b.Enabled() {
+				continue
+			}
+			s += ui.MutedText.Render(fmt.Sprintf("  %-15s", b.Help().Key)) + b.Help().Desc + "\n"
+		}
+	}
 
 	return s
 }
@@ -0,0 +1,50 @@
+// Package clock tells the time for everything that depends on today's date,
+// so tests can stop it and bug reports can be replayed at a given moment
+package clock
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the real clock
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Fixed returns a clock stopped at t
+func Fixed(t time.Time) Clock {
+	return fixedClock(t)
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// StartingAt returns a clock that reads t now and runs on from there, so
+// midnight still comes round while the TUI is open
+func StartingAt(t time.Time) Clock {
+	return offsetClock(time.Until(t))
+}
+
+type offsetClock time.Duration
+
+func (c offsetClock) Now() time.Time { return time.Now().Add(time.Duration(c)) }
+
+// Parse reads a local time given as 2006-01-02T15:04, with optional
+// seconds, or as a date alone for midnight
+func Parse(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, want YYYY-MM-DDTHH:MM", s)
+}
@@ -1,12 +1,17 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	_ "embed"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/vittolewerissa/hbt/internal/shared/clock"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
 	_ "modernc.org/sqlite"
 )
 
@@ -16,20 +21,53 @@ var schema string
 // DB wraps the database connection
 type DB struct {
 	*sql.DB
+
+	// Source is recorded with every change this connection makes
+	Source string
+
+	// Clock tells the time for everything that depends on today's date,
+	// and stamps every change
+	Clock clock.Clock
+
+	memory bool
+}
+
+// Timestamp formats t the way CURRENT_TIMESTAMP stores it
+func Timestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// Now returns the time by the database's clock as a timestamp, for rows
+// that would otherwise take CURRENT_TIMESTAMP
+func (d *DB) Now() string {
+	return Timestamp(d.Clock.Now())
 }
 
+// Memory is the path of a database kept in memory, for tests. It can't be
+// watched, as nothing outside the process can change it.
+const Memory = ":memory:"
+
 // Open opens or creates the database at the given path
 func Open(path string) (*DB, error) {
 	// Ensure the directory exists
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	if path != Memory {
+		dir := filepath.Dir(path)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create data directory: %w", err)
+		}
 	}
 
-	db, err := sql.Open("sqlite", path)
+	// Wait for other connections' locks rather than failing straight away
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)", path, Timeout.Milliseconds())
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	// One connection serves the whole process. Each connection to :memory:
+	// gets a database of its own, and PRAGMA data_version on the only
+	// connection changes just for other processes' commits, which is what
+	// the watcher looks for.
+	db.SetMaxOpenConns(1)
 
 	// Enable foreign keys
 	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
@@ -37,17 +75,33 @@ func Open(path string) (*DB, error) {
 		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
 	}
 
+	// Write-ahead logging lets other processes read while this one writes,
+	// and is kept in the database file once set
+	if _, err := db.Exec("PRAGMA journal_mode = WAL"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to enable WAL mode: %w", err)
+	}
+
 	// Run migrations
 	if err := migrate(db); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	return &DB{db}, nil
+	return &DB{DB: db, Source: SourceTUI, Clock: clock.System, memory: path == Memory}, nil
 }
 
 // migrate runs the schema migrations
 func migrate(db *sql.DB) error {
+	// The streak cache is filled in below if this is the run that creates it
+	var hasHabitStats bool
+	err := db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type='table' AND name='habit_stats')
+	`).Scan(&hasHabitStats)
+	if err != nil {
+		return err
+	}
+
 	// Run main schema
 	if _, err := db.Exec(schema); err != nil {
 		return err
@@ -61,6 +115,27 @@ func migrate(db *sql.DB) error {
 	// Add target_per_day column if it doesn't exist
 	_, _ = db.Exec("ALTER TABLE habits ADD COLUMN target_per_day INTEGER DEFAULT 1")
 
+	// Add position columns for manual ordering, seeding them in name order
+	if _, err := db.Exec("ALTER TABLE habits ADD COLUMN position INTEGER NOT NULL DEFAULT 0"); err == nil {
+		if _, err := db.Exec(`
+			UPDATE habits SET position = (
+				SELECT COUNT(*) FROM habits h2
+				WHERE h2.name < habits.name OR (h2.name = habits.name AND h2.id < habits.id)
+			)
+		`); err != nil {
+			return err
+		}
+	}
+	if _, err := db.Exec("ALTER TABLE categories ADD COLUMN position INTEGER NOT NULL DEFAULT 0"); err == nil {
+		if _, err := db.Exec(`
+			UPDATE categories SET position = (
+				SELECT COUNT(*) FROM categories c2 WHERE c2.name < categories.name
+			)
+		`); err != nil {
+			return err
+		}
+	}
+
 	// Remove UNIQUE constraint from completions table to allow multiple completions per day
 	// SQLite doesn't support dropping constraints, so we need to recreate the table
 	if err := migrateCompletionsTable(db); err != nil {
@@ -68,9 +143,82 @@ func migrate(db *sql.DB) error {
 		// The user can manually delete the database to get the new schema
 	}
 
+	if err := migrateCategoryColors(db); err != nil {
+		return err
+	}
+
+	if !hasHabitStats {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		if _, err := reindex(context.Background(), tx); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// legacyCategoryColors are the colors categories were given before colors
+// could be picked: the gray the app set, the schema's default white, and
+// none at all
+var legacyCategoryColors = map[string]bool{"#CCCCCC": true, "#FFFFFF": true, "": true}
+
+// migrateCategoryColors gives categories still on a legacy color a color
+// from the palette, in their manual order. It runs once, recorded in the
+// database's user_version, so a gray or white picked later is left alone.
+func migrateCategoryColors(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version >= 1 {
+		return nil
+	}
+
+	rows, err := db.Query("SELECT id, color FROM categories ORDER BY position, id")
+	if err != nil {
+		return err
+	}
+	var categories []model.Category
+	for rows.Next() {
+		var c model.Category
+		if err := rows.Scan(&c.ID, &c.Color); err != nil {
+			rows.Close()
+			return err
+		}
+		categories = append(categories, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for i, c := range categories {
+		if !legacyCategoryColors[strings.ToUpper(strings.TrimSpace(c.Color))] {
+			continue
+		}
+		categories[i].Color = model.NextColor(categories)
+		if _, err := tx.Exec("UPDATE categories SET color = ? WHERE id = ?", categories[i].Color, c.ID); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("PRAGMA user_version = 1"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 // migrateCompletionsTable removes the UNIQUE constraint from completions table
 func migrateCompletionsTable(db *sql.DB) error {
 	// Check if the constraint exists by trying to insert a duplicate
@@ -138,6 +286,7 @@ func migrateCompletionsTable(db *sql.DB) error {
 	// Step 5: Recreate indexes
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_completions_habit ON completions(habit_id)")
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_completions_date ON completions(completed_at)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_completions_habit_date ON completions(habit_id, completed_at)")
 
 	return nil
 }
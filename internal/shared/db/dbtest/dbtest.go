@@ -0,0 +1,74 @@
+// Package dbtest sets up in-memory databases for tests
+package dbtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/shared/clock"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+// Open opens a new in-memory database whose clock is stopped at now. It's
+// closed when the test ends.
+func Open(tb testing.TB, now time.Time) *db.DB {
+	tb.Helper()
+	database, err := db.Open(db.Memory)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { database.Close() })
+	database.Clock = clock.Fixed(now)
+	return database
+}
+
+// Exec runs a statement, failing the test if it errors, and returns the ID
+// of the row it inserted, if any
+func Exec(tb testing.TB, database *db.DB, query string, args ...any) int64 {
+	tb.Helper()
+	result, err := database.ExecContext(tb.Context(), query, args...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	id, _ := result.LastInsertId()
+	return id
+}
+
+// AddHabit inserts a habit as it is, without going through the habits
+// service, and returns its ID. The frequency and target default to once a
+// day, and the creation time to the database's clock.
+func AddHabit(tb testing.TB, database *db.DB, h model.Habit) int64 {
+	tb.Helper()
+	if h.FrequencyType == "" {
+		h.FrequencyType = model.FreqDaily
+	}
+	if h.TargetPerDay == 0 {
+		h.TargetPerDay = 1
+	}
+	if h.CreatedAt.IsZero() {
+		h.CreatedAt = database.Clock.Now()
+	}
+	var archivedAt *string
+	if h.ArchivedAt != nil {
+		t := db.Timestamp(*h.ArchivedAt)
+		archivedAt = &t
+	}
+	return Exec(tb, database, `
+		INSERT INTO habits (name, emoji, category_id, frequency_type, frequency_value, target_per_day, position, created_at, archived_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, h.Name, h.Emoji, h.CategoryID, h.FrequencyType, h.FrequencyValue, h.TargetPerDay, h.Position, db.Timestamp(h.CreatedAt), archivedAt)
+}
+
+// Complete inserts a completion of a habit on each date, given as
+// YYYY-MM-DD and repeated for more than one a day, then rebuilds the
+// cached streaks
+func Complete(tb testing.TB, database *db.DB, habitID int64, dates ...string) {
+	tb.Helper()
+	for _, date := range dates {
+		Exec(tb, database, `INSERT INTO completions (habit_id, completed_at) VALUES (?, ?)`, habitID, date)
+	}
+	if _, err := database.Reindex(tb.Context()); err != nil {
+		tb.Fatal(err)
+	}
+}
@@ -0,0 +1,199 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/vittolewerissa/hbt/internal/shared/clock"
+)
+
+// Sources of data changes recorded in the events table
+const (
+	SourceTUI    = "tui"
+	SourceCLI    = "cli"
+	SourceImport = "import"
+	SourceAPI    = "api"
+)
+
+// Kinds of change recorded in the events table
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Entities whose changes are recorded in the events table
+const (
+	EntityHabit      = "habit"
+	EntityCategory   = "category"
+	EntityCompletion = "completion"
+	EntitySetting    = "setting"
+	EntityCheckIn    = "checkin"
+)
+
+// Entities lists every entity in the events table
+var Entities = []string{EntityHabit, EntityCategory, EntityCompletion, EntitySetting, EntityCheckIn}
+
+// snapshotQueries capture a row of each entity as a JSON object
+var snapshotQueries = map[string]string{
+	EntityHabit: `SELECT json_object(
+		'id', id, 'name', name, 'description', description, 'emoji', emoji, 'category_id', category_id,
+		'frequency_type', frequency_type, 'frequency_value', frequency_value, 'target_per_day', target_per_day,
+		'position', position, 'created_at', created_at, 'archived_at', archived_at
+	) FROM habits WHERE id = ?`,
+	EntityCategory: `SELECT json_object(
+		'id', id, 'name', name, 'color', color, 'emoji', emoji, 'position', position, 'created_at', created_at
+	) FROM categories WHERE id = ?`,
+	EntityCompletion: `SELECT json_object(
+		'id', id, 'habit_id', habit_id, 'completed_at', completed_at, 'notes', notes
+	) FROM completions WHERE id = ?`,
+	EntitySetting: `SELECT json_object('key', key, 'value', value) FROM settings WHERE key = ?`,
+	EntityCheckIn: `SELECT json_object(
+		'date', date, 'mood', mood, 'energy', energy, 'notes', notes, 'created_at', created_at
+	) FROM checkins WHERE date = ?`,
+}
+
+// Change is a row being changed inside a transaction. Begin it before
+// touching the row and Log it once the row is written, before committing.
+type Change struct {
+	ctx    context.Context
+	tx     *sql.Tx
+	source string
+	clock  clock.Clock
+	entity string
+	before sql.NullString
+
+	// Key is the row's ID, or its key for settings and check-ins. A row
+	// being inserted has none until the insert, so set it before Log.
+	Key any
+}
+
+// BeginChange captures a row as it is before a change. key is nil for a
+// row that is about to be inserted.
+func (d *DB) BeginChange(ctx context.Context, tx *sql.Tx, entity string, key any) (*Change, error) {
+	c := &Change{ctx: ctx, tx: tx, source: d.Source, clock: d.Clock, entity: entity, Key: key}
+	if key == nil {
+		return c, nil
+	}
+	before, err := c.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	c.before = before
+	return c, nil
+}
+
+// Log appends the change to the events table with the row as it is now.
+// The action follows from whether the row existed before and after; nothing
+// is recorded if it didn't change.
+func (c *Change) Log() error {
+	after, err := c.snapshot()
+	if err != nil {
+		return err
+	}
+
+	var action string
+	switch {
+	case !c.before.Valid && !after.Valid:
+		return nil
+	case !c.before.Valid:
+		action = ActionCreate
+	case !after.Valid:
+		action = ActionDelete
+	case c.before.String == after.String:
+		return nil
+	default:
+		action = ActionUpdate
+	}
+
+	query := `
+		INSERT INTO events (occurred_at, source, entity, entity_id, action, before, after)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = c.tx.ExecContext(c.ctx, query, Timestamp(c.clock.Now()), c.source, c.entity, fmt.Sprint(c.Key), action, c.before, after)
+	return err
+}
+
+// snapshot returns the row as JSON, or null if it doesn't exist
+func (c *Change) snapshot() (sql.NullString, error) {
+	var row sql.NullString
+	query, ok := snapshotQueries[c.entity]
+	if !ok {
+		return row, fmt.Errorf("unknown entity %q", c.entity)
+	}
+	err := c.tx.QueryRowContext(c.ctx, query, c.Key).Scan(&row)
+	if err == sql.ErrNoRows {
+		return sql.NullString{}, nil
+	}
+	return row, err
+}
+
+// ExecChange runs a statement inside tx that changes the single row with
+// the given key, logging the change
+func (d *DB) ExecChange(ctx context.Context, tx *sql.Tx, entity string, key any, query string, args ...any) error {
+	c, err := d.BeginChange(ctx, tx, entity, key)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	return c.Log()
+}
+
+// ExecLogged runs a statement that changes the single row with the given
+// key in its own transaction, logging the change
+func (d *DB) ExecLogged(ctx context.Context, entity string, key any, query string, args ...any) error {
+	return d.LogChange(ctx, entity, key, func(tx *sql.Tx) (any, error) {
+		_, err := tx.ExecContext(ctx, query, args...)
+		return nil, err
+	})
+}
+
+// LogChange runs fn in a transaction that changes a single row, logging
+// the change. Use it for one-statement writes; key is nil when fn inserts
+// the row, in which case fn returns the new key.
+func (d *DB) LogChange(ctx context.Context, entity string, key any, fn func(tx *sql.Tx) (any, error)) error {
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	c, err := d.BeginChange(ctx, tx, entity, key)
+	if err != nil {
+		return err
+	}
+	newKey, err := fn(tx)
+	if err != nil {
+		return err
+	}
+	if newKey != nil {
+		c.Key = newKey
+	}
+	if err := c.Log(); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// QueryIDs returns the IDs selected by query inside tx, for changing rows
+// one at a time so each change is logged
+func QueryIDs(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
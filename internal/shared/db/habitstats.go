@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// rebuildHabitStatsQuery works out habit streaks and totals from scratch for
+// the completions matching a condition. Consecutive days share the same
+// julianday minus row number, which groups them into runs.
+const rebuildHabitStatsQuery = `
+	INSERT INTO habit_stats (habit_id, completions, days_completed, first_completed, last_completed, last_streak, best_streak)
+	WITH days AS (
+		SELECT habit_id, substr(completed_at, 1, 10) AS day, COUNT(*) AS n
+		FROM completions
+		WHERE %s
+		GROUP BY habit_id, day
+	),
+	numbered AS (
+		SELECT habit_id, day, julianday(day) - ROW_NUMBER() OVER (PARTITION BY habit_id ORDER BY day) AS run
+		FROM days
+	),
+	runs AS (
+		SELECT habit_id, COUNT(*) AS length, MAX(day) AS last_day
+		FROM numbered
+		GROUP BY habit_id, run
+	)
+	SELECT d.habit_id, SUM(d.n), COUNT(*), MIN(d.day), MAX(d.day),
+	       (SELECT length FROM runs r WHERE r.habit_id = d.habit_id ORDER BY r.last_day DESC LIMIT 1),
+	       (SELECT MAX(length) FROM runs r WHERE r.habit_id = d.habit_id)
+	FROM days d
+	GROUP BY d.habit_id
+`
+
+// RebuildHabitStats recomputes the cached streaks and totals of one habit
+// from its completions inside tx
+func RebuildHabitStats(ctx context.Context, tx *sql.Tx, habitID int64) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM habit_stats WHERE habit_id = ?`, habitID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(rebuildHabitStatsQuery, "habit_id = ?"), habitID)
+	return err
+}
+
+// Reindex rebuilds the cached streaks and totals of every habit from its
+// completions, returning how many habits have completions
+func (d *DB) Reindex(ctx context.Context) (int, error) {
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	n, err := reindex(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+// reindex rebuilds the habit_stats table inside tx
+func reindex(ctx context.Context, tx *sql.Tx) (int, error) {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM habit_stats`); err != nil {
+		return 0, err
+	}
+	result, err := tx.ExecContext(ctx, fmt.Sprintf(rebuildHabitStatsQuery, "1"))
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
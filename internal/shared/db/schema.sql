@@ -4,6 +4,7 @@ CREATE TABLE IF NOT EXISTS categories (
     name TEXT NOT NULL UNIQUE,
     color TEXT NOT NULL DEFAULT '#FFFFFF',
     emoji TEXT DEFAULT '📁',
+    position INTEGER NOT NULL DEFAULT 0,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -17,6 +18,7 @@ CREATE TABLE IF NOT EXISTS habits (
     frequency_type TEXT NOT NULL DEFAULT 'daily',
     frequency_value INTEGER DEFAULT 1,
     target_per_day INTEGER DEFAULT 1,
+    position INTEGER NOT NULL DEFAULT 0,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     archived_at DATETIME
 );
@@ -29,14 +31,59 @@ CREATE TABLE IF NOT EXISTS completions (
     notes TEXT DEFAULT ''
 );
 
+-- Streaks and totals per habit, kept up to date with its completions.
+-- last_streak is the run of days ending on last_completed. hbt reindex rebuilds it.
+CREATE TABLE IF NOT EXISTS habit_stats (
+    habit_id INTEGER PRIMARY KEY REFERENCES habits(id) ON DELETE CASCADE,
+    completions INTEGER NOT NULL DEFAULT 0,
+    days_completed INTEGER NOT NULL DEFAULT 0,
+    first_completed TEXT,
+    last_completed TEXT,
+    last_streak INTEGER NOT NULL DEFAULT 0,
+    best_streak INTEGER NOT NULL DEFAULT 0
+);
+
+-- Daily mood and energy check-ins, one per day
+CREATE TABLE IF NOT EXISTS checkins (
+    date TEXT PRIMARY KEY,
+    mood INTEGER NOT NULL CHECK (mood BETWEEN 1 AND 5),
+    energy INTEGER NOT NULL CHECK (energy BETWEEN 1 AND 5),
+    notes TEXT DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
 -- App settings
 CREATE TABLE IF NOT EXISTS settings (
     key TEXT PRIMARY KEY,
     value TEXT NOT NULL
 );
 
+-- Append-only log of every data change, with the row before and after as JSON
+CREATE TABLE IF NOT EXISTS events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    occurred_at DATETIME NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('tui', 'cli', 'import', 'api')),
+    entity TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    before TEXT,
+    after TEXT
+);
+
+CREATE TRIGGER IF NOT EXISTS events_no_update BEFORE UPDATE ON events
+BEGIN
+    SELECT RAISE(ABORT, 'events are append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS events_no_delete BEFORE DELETE ON events
+BEGIN
+    SELECT RAISE(ABORT, 'events are append-only');
+END;
+
 -- Indexes for common queries
 CREATE INDEX IF NOT EXISTS idx_habits_category ON habits(category_id);
 CREATE INDEX IF NOT EXISTS idx_habits_archived ON habits(archived_at);
 CREATE INDEX IF NOT EXISTS idx_completions_habit ON completions(habit_id);
 CREATE INDEX IF NOT EXISTS idx_completions_date ON completions(completed_at);
+CREATE INDEX IF NOT EXISTS idx_completions_habit_date ON completions(habit_id, completed_at);
+CREATE INDEX IF NOT EXISTS idx_events_entity ON events(entity, entity_id);
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Timeout is how long a database call may take before it's given up on.
+// SQLite also waits this long for another connection's lock.
+const Timeout = 5 * time.Second
+
+// ErrTimeout is reported when the database doesn't answer in time, usually
+// because another program is holding a lock on it
+var ErrTimeout = errors.New("the database didn't respond in time; another program may be holding it locked")
+
+// WithTimeout returns a context for one database call, or a few made
+// together, that is cancelled after Timeout
+func WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, Timeout)
+}
+
+// Explain replaces the errors a slow or locked database causes with
+// ErrTimeout, for showing to the user
+func Explain(err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqlite3.SQLITE_BUSY {
+		return ErrTimeout
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotWatchable is returned by Watch for an in-memory database, which
+// nothing outside the process can change
+var ErrNotWatchable = errors.New("an in-memory database can't be watched")
+
+// Watcher notices commits made to the database by other processes, such as
+// the hbt CLI or the sqlite3 shell
+type Watcher struct {
+	db      *DB
+	version int64
+}
+
+// Watch starts watching for changes. It reads PRAGMA data_version on the
+// process's one connection, which only changes when a different connection
+// commits, so this process's own writes don't count.
+func (d *DB) Watch(ctx context.Context) (*Watcher, error) {
+	if d.memory {
+		return nil, ErrNotWatchable
+	}
+	w := &Watcher{db: d}
+	var err error
+	if w.version, err = w.dataVersion(ctx); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Changed reports whether another process committed anything since the
+// last check
+func (w *Watcher) Changed(ctx context.Context) (bool, error) {
+	version, err := w.dataVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+	changed := version != w.version
+	w.version = version
+	return changed, nil
+}
+
+// Close stops watching. The connection belongs to the database, so there's
+// nothing to release.
+func (w *Watcher) Close() error {
+	return nil
+}
+
+// dataVersion reads the connection's data version
+func (w *Watcher) dataVersion(ctx context.Context) (int64, error) {
+	var version int64
+	err := w.db.QueryRowContext(ctx, "PRAGMA data_version").Scan(&version)
+	return version, err
+}
@@ -0,0 +1,184 @@
+// Package emoji is the full Unicode emoji set with CLDR short names and
+// keywords, for searching and picking emojis. The table is generated from
+// the Unicode emoji test data and CLDR annotations and embedded in the
+// binary.
+package emoji
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+)
+
+//go:generate go run gen.go
+
+//go:embed emoji.tsv
+var table string
+
+// Emoji is one fully-qualified emoji
+type Emoji struct {
+	Char     string
+	Name     string // CLDR short name, e.g. "person running"
+	Group    string // e.g. "People & Body"
+	Subgroup string // e.g. "person-activity"
+	tones    []string
+	words    string // CLDR keywords not already in the name
+}
+
+var (
+	all    []Emoji
+	byChar map[string]int // index into all, by emoji, tone variant and unqualified form
+)
+
+func init() {
+	lines := strings.Split(strings.TrimSuffix(table, "\n"), "\n")
+	all = make([]Emoji, 0, len(lines))
+	byChar = make(map[string]int, len(lines)*2)
+
+	for _, line := range lines {
+		fields := strings.Split(line, "\t")
+		e := Emoji{Char: fields[0], Name: fields[1], Group: fields[2], Subgroup: fields[3], words: fields[5]}
+		if fields[4] != "" {
+			e.tones = strings.Split(fields[4], " ")
+		}
+		i := len(all)
+		all = append(all, e)
+		for _, s := range append([]string{e.Char}, e.tones...) {
+			if _, ok := byChar[s]; !ok {
+				byChar[s] = i
+			}
+			if _, ok := byChar[unqualified(s)]; !ok {
+				byChar[unqualified(s)] = i
+			}
+		}
+	}
+}
+
+// unqualified drops the emoji presentation selectors from s, so emojis
+// typed or stored without them are still recognized
+func unqualified(s string) string {
+	return strings.ReplaceAll(s, "\uFE0F", "")
+}
+
+// All returns every emoji in Unicode's order
+func All() []Emoji {
+	return all
+}
+
+// Lookup finds the emoji s is, or is a skin tone variant of
+func Lookup(s string) (Emoji, bool) {
+	i, ok := byChar[s]
+	if !ok {
+		i, ok = byChar[unqualified(s)]
+	}
+	if !ok {
+		return Emoji{}, false
+	}
+	return all[i], true
+}
+
+// Keywords returns the words an emoji is found by: its name, CLDR keywords,
+// group and subgroup. It's empty for anything that isn't an emoji.
+func Keywords(s string) string {
+	e, ok := Lookup(s)
+	if !ok {
+		return ""
+	}
+	return e.Name + " " + e.keywords()
+}
+
+// keywords returns the words other than the name that e is found by
+func (e Emoji) keywords() string {
+	return strings.Join([]string{
+		e.words,
+		strings.ReplaceAll(e.Subgroup, "-", " "),
+		strings.ToLower(e.Group),
+	}, " ")
+}
+
+// WithTone returns s in skin tone t, or s unchanged if it doesn't come in
+// skin tones
+func WithTone(s string, t Tone) string {
+	e, ok := Lookup(s)
+	if !ok || len(e.tones) == 0 {
+		return s
+	}
+	if t == ToneNone {
+		return e.Char
+	}
+	return e.tones[t-1]
+}
+
+// Search returns the emojis matching every word of query, best matches
+// first: whole names, then names starting with the query, then names
+// containing a word starting with it, then keyword matches.
+func Search(query string) []string {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	whole := strings.Join(terms, " ")
+
+	type match struct {
+		emoji string
+		rank  int
+	}
+	var matches []match
+	for _, e := range all {
+		name := strings.ToLower(e.Name)
+		rank := 0
+		if name != whole {
+			rank = 1
+			for _, term := range terms {
+				r := rankTerm(term, name, e.keywords())
+				if r < 0 {
+					rank = -1
+					break
+				}
+				rank += r
+			}
+		}
+		if rank >= 0 {
+			matches = append(matches, match{e.Char, rank})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].rank < matches[j].rank
+	})
+	results := make([]string, len(matches))
+	for i, m := range matches {
+		results[i] = m.emoji
+	}
+	return results
+}
+
+// rankTerm scores how well one search word matches an emoji's name and
+// keywords, lower being better, or -1 if it doesn't
+func rankTerm(term, name, keywords string) int {
+	switch {
+	case strings.HasPrefix(name, term):
+		return 0
+	case hasWordPrefix(name, term):
+		return 1
+	case strings.Contains(name, term):
+		return 2
+	case hasWordPrefix(keywords, term):
+		return 3
+	case strings.Contains(keywords, term):
+		return 4
+	}
+	return -1
+}
+
+// hasWordPrefix reports whether any word of s starts with prefix
+func hasWordPrefix(s, prefix string) bool {
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ':' || r == '-' || r == ','
+	}) {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package emoji
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEveryEmojiIsTwoCellsWide(t *testing.T) {
+	for _, e := range All() {
+		for _, s := range append([]string{e.Char}, e.tones...) {
+			if w := Width(s); w != 2 {
+				t.Errorf("%q (%s) is %d cells wide, want 2", s, e.Name, w)
+			}
+		}
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"✌🏽", 2},  // skin tone of an emoji that's text by default
+		{"#️⃣", 2}, // keycap
+		{"❤", 1},   // text presentation
+		{"a🏃b", 4}, // wide already
+		{"\x1b[1m1️⃣\x1b[0m!", 3},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.in); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestedAreKnownAndUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, s := range Suggested {
+		e, ok := Lookup(s)
+		if !ok {
+			t.Errorf("%q is not an emoji", s)
+			continue
+		}
+		if e.Char != s {
+			t.Errorf("%q should be written fully-qualified as %q", s, e.Char)
+		}
+		if seen[s] {
+			t.Errorf("%q is suggested twice", s)
+		}
+		seen[s] = true
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"🏃", "person running"},
+		{"🏃🏽", "person running"},              // skin tone variant
+		{"❤", "red heart"},                    // without the presentation selector
+		{"👨‍👩‍👧", "family: man, woman, girl"}, // ZWJ sequence
+	}
+	for _, tt := range tests {
+		e, ok := Lookup(tt.in)
+		if !ok || e.Name != tt.want {
+			t.Errorf("Lookup(%q) = %q, %v, want %q", tt.in, e.Name, ok, tt.want)
+		}
+	}
+	if _, ok := Lookup("a"); ok {
+		t.Error(`Lookup("a") found an emoji`)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		query string
+		first string
+		has   []string
+	}{
+		{query: "red heart", first: "❤️"},
+		{query: "run", has: []string{"🏃", "👟"}},
+		{query: "person run", first: "🏃"},
+		{query: "Books", first: "📚"},
+		{query: "muscle flex", has: []string{"💪"}}, // keywords
+		{query: "pizza slice", has: []string{"🍕"}},
+		{query: "animal mammal", has: []string{"🐶", "🐱"}}, // group and subgroup
+	}
+	for _, tt := range tests {
+		got := Search(tt.query)
+		if tt.first != "" && (len(got) == 0 || got[0] != tt.first) {
+			t.Errorf("Search(%q) = %q..., want %q first", tt.query, got[:min(len(got), 5)], tt.first)
+		}
+		for _, want := range tt.has {
+			if !slices.Contains(got, want) {
+				t.Errorf("Search(%q) is missing %q", tt.query, want)
+			}
+		}
+	}
+
+	if got := Search("zzqx"); len(got) != 0 {
+		t.Errorf(`Search("zzqx") = %q, want nothing`, got)
+	}
+	if got := Search("  "); got != nil {
+		t.Errorf(`Search("  ") = %q, want nil`, got)
+	}
+}
+
+func TestWithTone(t *testing.T) {
+	tests := []struct {
+		in   string
+		tone Tone
+		want string
+	}{
+		{"👋", ToneMedium, "👋🏽"},
+		{"👋🏿", ToneLight, "👋🏻"},
+		{"👋🏿", ToneNone, "👋"},
+		{"🧑‍💻", ToneDark, "🧑🏿‍💻"},
+		{"📚", ToneDark, "📚"}, // no skin tones
+		{"x", ToneDark, "x"},
+	}
+	for _, tt := range tests {
+		if got := WithTone(tt.in, tt.tone); got != tt.want {
+			t.Errorf("WithTone(%q, %v) = %q, want %q", tt.in, tt.tone, got, tt.want)
+		}
+	}
+
+	if ParseTone("9") != ToneNone || ParseTone("x") != ToneNone || ParseTone("2") != ToneMediumLight {
+		t.Error("ParseTone accepted an invalid tone or rejected a valid one")
+	}
+	if ToneDark.Next() != ToneNone {
+		t.Errorf("ToneDark.Next() = %v, want %v", ToneDark.Next(), ToneNone)
+	}
+}
+
+func TestRecents(t *testing.T) {
+	var r Recents
+	for _, e := range []string{"📚", "🏃", "📚", "💧"} {
+		r = r.Use(e)
+	}
+	// Used twice beats used most recently
+	if got, want := r.Emojis(), []string{"📚", "💧", "🏃"}; !slices.Equal(got, want) {
+		t.Errorf("Emojis() = %q, want %q", got, want)
+	}
+
+	stored := ParseRecents(r.String())
+	if !slices.Equal(stored.Emojis(), r.Emojis()) {
+		t.Errorf("ParseRecents(%q) = %q, want %q", r.String(), stored.Emojis(), r.Emojis())
+	}
+	if got := ParseRecents("📚:x,:1,🏃"); len(got) != 0 {
+		t.Errorf("ParseRecents kept malformed entries: %v", got)
+	}
+
+	for _, e := range All()[:MaxRecents+5] {
+		r = r.Use(e.Char)
+	}
+	if len(r) != MaxRecents {
+		t.Errorf("len = %d after many uses, want %d", len(r), MaxRecents)
+	}
+}
@@ -0,0 +1,229 @@
+//go:build ignore
+
+// gen.go turns the Unicode emoji test file and the CLDR English emoji
+// annotations into emoji.tsv, the table embedded in the emoji package. Each
+// line is an emoji, its CLDR short name, group, subgroup, space-separated
+// skin tone variants and CLDR keywords, split by tabs. There's no header
+// line since "#" is itself an emoji.
+//
+// Run it with go generate, or with -in and -annotations to use copies of
+// emoji-test.txt and annotations/en.xml that have already been downloaded.
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	source            = "https://unicode.org/Public/emoji/15.1/emoji-test.txt"
+	annotationsSource = "https://raw.githubusercontent.com/unicode-org/cldr/release-44/common/annotations/en.xml"
+)
+
+// entry is one emoji and its skin tone variants, light to dark
+type entry struct {
+	emoji, name, group, subgroup string
+	tones                        [5]string
+	keywords                     string
+}
+
+func main() {
+	in := flag.String("in", "", "emoji-test.txt to read instead of downloading it")
+	annotations := flag.String("annotations", "", "CLDR annotations en.xml to read instead of downloading it")
+	out := flag.String("out", "emoji.tsv", "file to write")
+	flag.Parse()
+
+	r, err := open(*in, source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	entries, err := parse(r)
+	r.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	r, err = open(*annotations, annotationsSource)
+	if err != nil {
+		log.Fatal(err)
+	}
+	keywords, err := parseAnnotations(r)
+	r.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, e := range entries {
+		e.keywords = keywordsFor(keywords[unqualified(e.emoji)], e.name)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	w := bufio.NewWriter(f)
+	for _, e := range entries {
+		tones := ""
+		if e.tones[0] != "" {
+			tones = strings.Join(e.tones[:], " ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.emoji, e.name, e.group, e.subgroup, tones, e.keywords)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// open reads path, or downloads url if path is empty
+func open(path, url string) (io.ReadCloser, error) {
+	if path != "" {
+		return os.Open(path)
+	}
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// parse reads the fully-qualified emoji in file order, folding each skin
+// tone variant into the emoji it modifies. Variants that mix tones, such as
+// couples with a different tone for each person, are left out, as is the
+// Component group of bare tones and hair styles.
+func parse(r io.Reader) ([]*entry, error) {
+	var entries []*entry
+	bases := make(map[string]*entry) // by code points without tones or VS16
+	var group, subgroup string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if g, ok := strings.CutPrefix(line, "# group: "); ok {
+			group = g
+			continue
+		}
+		if s, ok := strings.CutPrefix(line, "# subgroup: "); ok {
+			subgroup = s
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") || group == "Component" {
+			continue
+		}
+
+		fields, comment, _ := strings.Cut(line, "#")
+		codes, status, _ := strings.Cut(fields, ";")
+		if strings.TrimSpace(status) != "fully-qualified" {
+			continue
+		}
+
+		var emoji strings.Builder
+		var key []string
+		tone := 0
+		mixed := false
+		for _, code := range strings.Fields(codes) {
+			cp, err := strconv.ParseUint(code, 16, 32)
+			if err != nil {
+				return nil, err
+			}
+			emoji.WriteRune(rune(cp))
+			switch {
+			case cp >= 0x1F3FB && cp <= 0x1F3FF:
+				t := int(cp-0x1F3FB) + 1
+				if tone != 0 && tone != t {
+					mixed = true
+				}
+				tone = t
+			case cp == 0xFE0F:
+			default:
+				key = append(key, code)
+			}
+		}
+
+		// The comment is "<emoji> E<version> <name>"
+		parts := strings.SplitN(strings.TrimSpace(comment), " ", 3)
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("unexpected line: %q", line)
+		}
+		name := parts[2]
+
+		k := strings.Join(key, " ")
+		if tone == 0 {
+			e := &entry{emoji: emoji.String(), name: name, group: group, subgroup: subgroup}
+			entries = append(entries, e)
+			if _, ok := bases[k]; !ok {
+				bases[k] = e
+			}
+			continue
+		}
+		if base, ok := bases[k]; ok && !mixed {
+			base.tones[tone-1] = emoji.String()
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// parseAnnotations reads the keywords of each emoji from CLDR annotations,
+// by the emoji without presentation selectors. The entries with a type are
+// the spoken names, which are left out since the test file has the names.
+func parseAnnotations(r io.Reader) (map[string][]string, error) {
+	var doc struct {
+		Annotations []struct {
+			CP   string `xml:"cp,attr"`
+			Type string `xml:"type,attr"`
+			Text string `xml:",chardata"`
+		} `xml:"annotations>annotation"`
+	}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	keywords := make(map[string][]string)
+	for _, a := range doc.Annotations {
+		if a.Type != "" {
+			continue
+		}
+		for _, k := range strings.Split(a.Text, "|") {
+			if k = strings.TrimSpace(k); k != "" {
+				keywords[unqualified(a.CP)] = append(keywords[unqualified(a.CP)], k)
+			}
+		}
+	}
+	return keywords, nil
+}
+
+// keywordsFor joins the words of an emoji's keywords that aren't already
+// in its name, once each
+func keywordsFor(keywords []string, name string) string {
+	seen := strings.Fields(strings.ToLower(name))
+	var words []string
+	for _, k := range keywords {
+		for _, word := range strings.Fields(strings.ToLower(k)) {
+			if !slices.Contains(seen, word) {
+				seen = append(seen, word)
+				words = append(words, word)
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// unqualified drops the emoji presentation selectors from s, which CLDR
+// leaves out of most annotations
+func unqualified(s string) string {
+	return strings.ReplaceAll(s, "\uFE0F", "")
+}
@@ -0,0 +1,79 @@
+package emoji
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxRecents is how many recently used emojis are remembered
+const MaxRecents = 24
+
+// recentDecay is how much every other emoji's weight shrinks each time one
+// is used, so emojis used often stay near the front while ones not used in
+// a while drift back
+const recentDecay = 0.9
+
+// Recent is a recently used emoji and how much it's been used, weighted
+// towards recent uses
+type Recent struct {
+	Emoji  string
+	Weight float64
+}
+
+// Recents are recently used emojis, most weighted first. They're stored as
+// "emoji:weight" pairs separated by commas.
+type Recents []Recent
+
+// ParseRecents reads stored recents, skipping anything malformed
+func ParseRecents(s string) Recents {
+	var r Recents
+	for _, part := range strings.Split(s, ",") {
+		e, w, ok := strings.Cut(part, ":")
+		if !ok || e == "" {
+			continue
+		}
+		weight, err := strconv.ParseFloat(w, 64)
+		if err != nil {
+			continue
+		}
+		r = append(r, Recent{Emoji: e, Weight: weight})
+	}
+	return r
+}
+
+// String encodes the recents for storing
+func (r Recents) String() string {
+	parts := make([]string, len(r))
+	for i, recent := range r {
+		parts[i] = fmt.Sprintf("%s:%.3g", recent.Emoji, recent.Weight)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Use returns the recents after e has been picked once more
+func (r Recents) Use(e string) Recents {
+	used := make(Recents, 0, len(r)+1)
+	used = append(used, Recent{Emoji: e, Weight: 1})
+	for _, recent := range r {
+		if recent.Emoji == e {
+			used[0].Weight += recent.Weight * recentDecay
+			continue
+		}
+		used = append(used, Recent{Emoji: recent.Emoji, Weight: recent.Weight * recentDecay})
+	}
+	sort.SliceStable(used, func(i, j int) bool {
+		return used[i].Weight > used[j].Weight
+	})
+	return used[:min(len(used), MaxRecents)]
+}
+
+// Emojis returns just the emojis, most weighted first
+func (r Recents) Emojis() []string {
+	emojis := make([]string, len(r))
+	for i, recent := range r {
+		emojis[i] = recent.Emoji
+	}
+	return emojis
+}
@@ -0,0 +1,41 @@
+package emoji
+
+import "strconv"
+
+// Tone is a skin tone for emojis of people and body parts
+type Tone int
+
+const (
+	ToneNone Tone = iota // the default yellow
+	ToneLight
+	ToneMediumLight
+	ToneMedium
+	ToneMediumDark
+	ToneDark
+)
+
+var toneNames = []string{"default", "light", "medium-light", "medium", "medium-dark", "dark"}
+
+// ParseTone converts a stored tone number, falling back to ToneNone
+func ParseTone(s string) Tone {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < int(ToneNone) || n > int(ToneDark) {
+		return ToneNone
+	}
+	return Tone(n)
+}
+
+// Next returns the following tone, wrapping from dark back to the default
+func (t Tone) Next() Tone {
+	return (t + 1) % Tone(len(toneNames))
+}
+
+// String returns the tone's name
+func (t Tone) String() string {
+	return toneNames[t]
+}
+
+// Swatch returns a raised hand in the tone, to show which one is in use
+func (t Tone) Swatch() string {
+	return WithTone("✋", t)
+}
@@ -0,0 +1,44 @@
+package emoji
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rivo/uniseg"
+)
+
+// Width returns how many cells s takes in a terminal, ignoring escape
+// sequences. It's ansi.StringWidth except that emoji sequences are two
+// cells: that measures a keycap, or a skin tone of an emoji like ✌️ that
+// is text by default, by its first character alone and counts one.
+func Width(s string) int {
+	s = ansi.Strip(s)
+	width := 0
+	state := -1
+	for s != "" {
+		var cluster string
+		var w int
+		cluster, s, w, state = uniseg.FirstGraphemeClusterInString(s, state)
+		if w < 2 && isSequence(cluster) {
+			w = 2
+		}
+		width += w
+	}
+	return width
+}
+
+// isSequence reports whether cluster is a character followed by the emoji
+// presentation selector, a keycap or a skin tone
+func isSequence(cluster string) bool {
+	for i, r := range cluster {
+		if i > 0 && (r == 0xFE0F || r == 0x20E3 || r >= 0x1F3FB && r <= 0x1F3FF) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pad adds spaces to s up to width cells, measured by Width
+func Pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-Width(s), 0))
+}
@@ -0,0 +1,44 @@
+package model
+
+import "testing"
+
+func TestNormalizeColor(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"#4ECDC4", "#4ECDC4", true},
+		{"4ecdc4", "#4ECDC4", true},
+		{" #4cd ", "#44CCDD", true},
+		{"#4ECDC", "", false},
+		{"#GGGGGG", "", false},
+		{"red", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizeColor(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("NormalizeColor(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNextColor(t *testing.T) {
+	if got := NextColor(nil); got != DefaultColors[0] {
+		t.Errorf("NextColor(nil) = %q, want %q", got, DefaultColors[0])
+	}
+
+	categories := []Category{{Color: DefaultColors[0]}, {Color: "#cccccc"}, {Color: DefaultColors[2]}}
+	if got := NextColor(categories); got != DefaultColors[1] {
+		t.Errorf("NextColor skipping used colors = %q, want %q", got, DefaultColors[1])
+	}
+
+	categories = nil
+	for _, color := range DefaultColors {
+		categories = append(categories, Category{Color: color})
+	}
+	if got := NextColor(categories); got != DefaultColors[0] {
+		t.Errorf("NextColor with every color used = %q, want %q", got, DefaultColors[0])
+	}
+}
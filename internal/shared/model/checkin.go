@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// CheckIn is a daily mood and energy rating, each from 1 to 5
+type CheckIn struct {
+	Date   time.Time // Date only (no time component)
+	Mood   int
+	Energy int
+	Notes  string
+}
+
+// Check-in ratings range from MinRating to MaxRating
+const (
+	MinRating = 1
+	MaxRating = 5
+)
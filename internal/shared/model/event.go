@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Event is one change recorded in the audit log
+type Event struct {
+	ID         int64
+	OccurredAt time.Time
+	Source     string // tui, cli, import or api
+	Entity     string // habit, category, completion, setting or checkin
+	EntityID   string
+	Action     string // create, update or delete
+	Before     string // the row as JSON before the change, empty if it was created
+	After      string // the row as JSON after the change, empty if it was deleted
+}
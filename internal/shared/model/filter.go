@@ -0,0 +1,101 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/vittolewerissa/hbt/internal/shared/emoji"
+)
+
+// Filter chip tokens recognised in a filter query
+const (
+	FilterDueToken      = "is:due"
+	FilterNotDoneToken  = "is:todo"
+	FilterCategoryToken = "category:"
+)
+
+// HabitFilter narrows a habit list. It is parsed from the text typed after
+// "/", where plain words match habit text and tokens act as chips:
+//
+//	run is:due is:todo category:health
+type HabitFilter struct {
+	Terms    []string // lowercase words that must all match
+	Category string   // lowercase category name prefix, from category:x
+	DueOnly  bool     // only habits due today, from is:due
+	NotDone  bool     // only habits not yet completed today, from is:todo
+}
+
+// ParseHabitFilter parses a filter query into a HabitFilter
+func ParseHabitFilter(query string) HabitFilter {
+	var f HabitFilter
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		switch {
+		case word == FilterDueToken:
+			f.DueOnly = true
+		case word == FilterNotDoneToken:
+			f.NotDone = true
+		case strings.HasPrefix(word, FilterCategoryToken):
+			f.Category = strings.TrimPrefix(word, FilterCategoryToken)
+		default:
+			f.Terms = append(f.Terms, word)
+		}
+	}
+	return f
+}
+
+// IsEmpty returns true if the filter matches every habit
+func (f HabitFilter) IsEmpty() bool {
+	return len(f.Terms) == 0 && f.Category == "" && !f.DueOnly && !f.NotDone
+}
+
+// Chips returns short labels for the active filter chips
+func (f HabitFilter) Chips() []string {
+	var chips []string
+	if f.DueOnly {
+		chips = append(chips, "due only")
+	}
+	if f.NotDone {
+		chips = append(chips, "not done")
+	}
+	if f.Category != "" {
+		chips = append(chips, "category: "+f.Category)
+	}
+	return chips
+}
+
+// Matches reports whether the habit passes the filter. due and done describe
+// the habit's status today and are only consulted by the status chips.
+func (f HabitFilter) Matches(h *Habit, due, done bool) bool {
+	if f.DueOnly && !due {
+		return false
+	}
+	if f.NotDone && done {
+		return false
+	}
+
+	var categoryName, categoryEmoji string
+	if h.Category != nil {
+		categoryName = strings.ToLower(h.Category.Name)
+		categoryEmoji = h.Category.Emoji
+	}
+	if f.Category != "" && !strings.HasPrefix(categoryName, f.Category) {
+		return false
+	}
+
+	if len(f.Terms) == 0 {
+		return true
+	}
+
+	text := strings.ToLower(strings.Join([]string{
+		h.Name,
+		h.Description,
+		categoryName,
+		emoji.Keywords(h.Emoji),
+		emoji.Keywords(categoryEmoji),
+	}, " "))
+	for _, term := range f.Terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
@@ -21,6 +21,7 @@ type Habit struct {
 	FrequencyType  FrequencyType
 	FrequencyValue int // times per week if FrequencyType is times_per_week
 	TargetPerDay   int // how many times to complete per day
+	Position       int // manual sort order
 	CreatedAt      time.Time
 	ArchivedAt     *time.Time
 
@@ -0,0 +1,31 @@
+package model
+
+import "testing"
+
+func TestIsDueToday(t *testing.T) {
+	tests := []struct {
+		name     string
+		habit    Habit
+		thisWeek int
+		wantDue  bool
+	}{
+		{"daily, not done", Habit{FrequencyType: FreqDaily}, 0, true},
+		{"daily, done all week", Habit{FrequencyType: FreqDaily}, 7, true},
+		{"weekly, not done", Habit{FrequencyType: FreqWeekly}, 0, true},
+		{"weekly, done", Habit{FrequencyType: FreqWeekly}, 1, false},
+		{"weekly, done twice", Habit{FrequencyType: FreqWeekly}, 2, false},
+		{"3 a week, none yet", Habit{FrequencyType: FreqTimesPerWeek, FrequencyValue: 3}, 0, true},
+		{"3 a week, 2 done", Habit{FrequencyType: FreqTimesPerWeek, FrequencyValue: 3}, 2, true},
+		{"3 a week, 3 done", Habit{FrequencyType: FreqTimesPerWeek, FrequencyValue: 3}, 3, false},
+		{"3 a week, 4 done", Habit{FrequencyType: FreqTimesPerWeek, FrequencyValue: 3}, 4, false},
+		{"unknown frequency", Habit{FrequencyType: "monthly"}, 5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.habit.IsDueToday(tt.thisWeek); got != tt.wantDue {
+				t.Errorf("IsDueToday(%d) = %v, want %v", tt.thisWeek, got, tt.wantDue)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package model
+
+import "strings"
+
+// SortMode defines the order habits are listed in
+type SortMode string
+
+const (
+	SortManual   SortMode = "manual"    // by position
+	SortName     SortMode = "name"      // alphabetical
+	SortStreak   SortMode = "streak"    // longest current streak first
+	SortDueFirst SortMode = "due-first" // habits due today first, then by position
+	SortCategory SortMode = "category"  // grouped by category, then by position
+)
+
+// SortModes lists the sort modes in the order they are cycled through
+var SortModes = []SortMode{SortCategory, SortManual, SortName, SortStreak, SortDueFirst}
+
+// ParseSortMode returns the sort mode named by s, falling back to SortCategory
+func ParseSortMode(s string) SortMode {
+	for _, mode := range SortModes {
+		if string(mode) == s {
+			return mode
+		}
+	}
+	return SortCategory
+}
+
+// Next returns the sort mode after m in SortModes
+func (m SortMode) Next() SortMode {
+	for i, mode := range SortModes {
+		if mode == m {
+			return SortModes[(i+1)%len(SortModes)]
+		}
+	}
+	return SortModes[0]
+}
+
+// Manual returns true if the mode follows habit positions, so moving a habit
+// changes what is shown
+func (m SortMode) Manual() bool {
+	return m == SortManual || m == SortCategory
+}
+
+// HabitLess reports whether habit a sorts before b in the given mode. aDue
+// and bDue are the habits' due-today status, used by SortDueFirst.
+func HabitLess(mode SortMode, a, b *Habit, aDue, bDue bool) bool {
+	switch mode {
+	case SortName:
+		if !strings.EqualFold(a.Name, b.Name) {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+	case SortStreak:
+		if a.CurrentStreak != b.CurrentStreak {
+			return a.CurrentStreak > b.CurrentStreak
+		}
+	case SortDueFirst:
+		if aDue != bDue {
+			return aDue
+		}
+	case SortCategory:
+		if ac, bc := categoryOrder(a), categoryOrder(b); ac != bc {
+			return ac.less(bc)
+		}
+	}
+
+	if a.Position != b.Position {
+		return a.Position < b.Position
+	}
+	return a.ID < b.ID
+}
+
+// categoryKey orders categories by position, then name, with
+// uncategorized habits last
+type categoryKey struct {
+	none     bool
+	position int
+	name     string
+}
+
+func categoryOrder(h *Habit) categoryKey {
+	if h.Category == nil {
+		return categoryKey{none: true}
+	}
+	return categoryKey{position: h.Category.Position, name: h.Category.Name}
+}
+
+func (k categoryKey) less(o categoryKey) bool {
+	if k.none != o.none {
+		return o.none
+	}
+	if k.position != o.position {
+		return k.position < o.position
+	}
+	return k.name < o.name
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+// CategoryColor returns a category's color for the terminal, or no color if
+// it isn't a hex color. Terminals with 256 colors get the nearest one, and
+// terminals with only 16 get the bright color closest in hue, or white or
+// gray for grays, so categories still look different.
+func CategoryColor(hex string) lipgloss.TerminalColor {
+	hex, ok := model.NormalizeColor(hex)
+	if !ok {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.CompleteColor{TrueColor: hex, ANSI256: hex, ANSI: ansiColor(hex)}
+}
+
+// CategoryStyle returns style in a category's color, or unchanged if the
+// category has no usable color
+func CategoryStyle(style lipgloss.Style, hex string) lipgloss.Style {
+	if _, ok := model.NormalizeColor(hex); !ok {
+		return style
+	}
+	return style.Foreground(CategoryColor(hex))
+}
+
+// ColorSwatch renders a small block in a category's color, or blank space
+// if it has no usable color
+func ColorSwatch(hex string) string {
+	if _, ok := model.NormalizeColor(hex); !ok {
+		return "  "
+	}
+	return lipgloss.NewStyle().Foreground(CategoryColor(hex)).Render("██")
+}
+
+// ansiColor picks the 16-color ANSI code for a normalized "#RRGGBB" color
+func ansiColor(hex string) string {
+	rgb, _ := strconv.ParseUint(hex[1:], 16, 32)
+	r := float64(rgb>>16) / 255
+	g := float64(rgb>>8&0xFF) / 255
+	b := float64(rgb&0xFF) / 255
+
+	hi, lo := max(r, g, b), min(r, g, b)
+	if hi-lo < 0.12 {
+		if hi < 0.5 {
+			return "8" // gray
+		}
+		return "7" // white
+	}
+
+	var hue float64
+	switch hi {
+	case r:
+		hue = math.Mod((g-b)/(hi-lo), 6) * 60
+	case g:
+		hue = ((b-r)/(hi-lo) + 2) * 60
+	default:
+		hue = ((r-g)/(hi-lo) + 4) * 60
+	}
+	if hue < 0 {
+		hue += 360
+	}
+
+	switch {
+	case hue < 20 || hue >= 330:
+		return "9" // red
+	case hue < 70:
+		return "11" // yellow
+	case hue < 160:
+		return "10" // green
+	case hue < 185:
+		return "14" // cyan
+	case hue < 260:
+		return "12" // blue
+	default:
+		return "13" // magenta
+	}
+}
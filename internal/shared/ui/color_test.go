@@ -0,0 +1,24 @@
+package ui
+
+import "testing"
+
+func TestANSIColor(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want string
+	}{
+		{"#FF6B6B", "9"},  // red
+		{"#FFEAA7", "11"}, // yellow
+		{"#96CEB4", "10"}, // green
+		{"#4ECDC4", "14"}, // teal
+		{"#45B7D1", "12"}, // blue
+		{"#DDA0DD", "13"}, // plum
+		{"#CCCCCC", "7"},  // light gray
+		{"#333333", "8"},  // dark gray
+	}
+	for _, tt := range tests {
+		if got := ansiColor(tt.hex); got != tt.want {
+			t.Errorf("ansiColor(%q) = %q, want %q", tt.hex, got, tt.want)
+		}
+	}
+}
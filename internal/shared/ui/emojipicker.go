@@ -0,0 +1,378 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/vittolewerissa/hbt/internal/shared/emoji"
+)
+
+// Emoji grid dimensions shared by rendering, scrolling and click handling.
+// Each emoji takes a fixed cell of a bracket, two columns and a bracket so
+// the grid lines up whatever the emoji.
+const (
+	emojisPerRow     = 8
+	emojiVisibleRows = 8
+	emojiCellWidth   = 4
+)
+
+// EmojiPickedMsg is sent when an emoji is picked, so it can be remembered
+// as recently used
+type EmojiPickedMsg struct {
+	Emoji string
+}
+
+// SkinToneChangedMsg is sent when the picker's skin tone is changed, so it
+// can be remembered for next time
+type SkinToneChangedMsg struct {
+	Tone emoji.Tone
+}
+
+// EmojiPicker is the modal for choosing an emoji for a form field. With no
+// search it shows recently used emojis followed by suggestions; searching
+// covers every Unicode emoji by name and keyword.
+type EmojiPicker struct {
+	search  textinput.Model
+	recents []string
+	tone    emoji.Tone
+	items   []string // the emojis in the grid, in the chosen skin tone
+	index   int      // selected item, or -1 for "(none)"
+	offset  int      // first visible grid row
+	value   string
+	open    bool
+}
+
+// NewEmojiPicker creates a closed picker with value as the current emoji
+func NewEmojiPicker(value string) EmojiPicker {
+	search := textinput.New()
+	search.Placeholder = "Search emojis..."
+	search.CharLimit = 50
+	search.Width = 30
+	return EmojiPicker{search: search, value: value}
+}
+
+// SetPrefs sets the recently used emojis, most used first, and the skin
+// tone to show emojis in
+func (p *EmojiPicker) SetPrefs(recents []string, tone emoji.Tone) {
+	p.recents = recents
+	p.tone = tone
+	p.refresh()
+}
+
+// Value returns the chosen emoji, or "" for none
+func (p EmojiPicker) Value() string {
+	return p.value
+}
+
+// Clear removes the chosen emoji
+func (p *EmojiPicker) Clear() {
+	p.value = ""
+}
+
+// IsOpen returns true while the picker modal is showing
+func (p EmojiPicker) IsOpen() bool {
+	return p.open
+}
+
+// Open shows the picker with the current emoji selected, or "(none)"
+func (p *EmojiPicker) Open() tea.Cmd {
+	p.open = true
+	p.search.SetValue("")
+	p.refresh()
+	p.index = -1
+	if p.value != "" {
+		p.index = max(slices.Index(p.items, p.value), 0)
+	}
+	p.follow()
+	return p.search.Focus()
+}
+
+// Close hides the picker without changing the chosen emoji
+func (p *EmojiPicker) Close() {
+	p.open = false
+	p.search.Blur()
+}
+
+// Update handles a key while the picker is open. Typing searches, arrow
+// keys move around the grid and Select picks.
+func (p *EmojiPicker) Update(msg tea.KeyMsg, keys KeyMap) tea.Cmd {
+	switch {
+	case MatchesNonText(msg, keys.Back):
+		p.Close()
+	case MatchesNonText(msg, keys.Select):
+		return p.pick()
+	case MatchesNonText(msg, keys.SkinTone):
+		p.tone = p.tone.Next()
+		p.refresh()
+		tone := p.tone
+		return func() tea.Msg { return SkinToneChangedMsg{Tone: tone} }
+	case MatchesNonText(msg, keys.Left):
+		p.index--
+		if p.index < -1 {
+			p.index = len(p.items) - 1
+		}
+	case MatchesNonText(msg, keys.Right):
+		p.index++
+		if p.index >= len(p.items) {
+			p.index = -1
+		}
+	case MatchesNonText(msg, keys.Up):
+		if p.index >= 0 {
+			p.index = max(p.index-emojisPerRow, -1)
+		}
+	case MatchesNonText(msg, keys.Down):
+		if p.index == -1 && len(p.items) > 0 {
+			p.index = 0
+		} else if p.index+emojisPerRow < len(p.items) {
+			p.index += emojisPerRow
+		}
+	case MatchesNonText(msg, keys.PageUp):
+		p.index = max(p.index-emojisPerRow*emojiVisibleRows, -1)
+	case MatchesNonText(msg, keys.PageDown):
+		p.index = min(p.index+emojisPerRow*emojiVisibleRows, len(p.items)-1)
+	default:
+		before := p.search.Value()
+		var cmd tea.Cmd
+		p.search, cmd = p.search.Update(msg)
+		if p.search.Value() != before {
+			p.refresh()
+			// Enter picks the best match straight after typing
+			p.index = min(0, len(p.items)-1)
+			p.offset = 0
+		}
+		return cmd
+	}
+	p.follow()
+	return nil
+}
+
+// Scroll moves the selection by one row per wheel step
+func (p *EmojiPicker) Scroll(delta int) {
+	next := p.index + delta*emojisPerRow
+	if p.index == -1 {
+		if delta < 0 {
+			return
+		}
+		next = 0
+	}
+	if next < 0 {
+		next = -1
+	}
+	if next < len(p.items) {
+		p.index = next
+	}
+	p.follow()
+}
+
+// Click picks the emoji or "(none)" under a click; clicking outside the
+// box closes the picker
+func (p *EmojiPicker) Click(msg ModalClickMsg, keys KeyMap) tea.Cmd {
+	width, height := lipgloss.Size(p.View(keys))
+	if msg.Row < 0 || msg.Col < 0 || msg.Row >= height || msg.Col >= width {
+		p.Close()
+		return nil
+	}
+
+	row := msg.Row - ModalContentRow
+	col := msg.Col - ModalContentCol
+	if row == lipgloss.Height(p.viewTop())-1 {
+		p.index = -1
+		return p.pick()
+	}
+
+	gridRow := row - (lipgloss.Height(p.viewHeader()) - 1)
+	if gridRow < 0 || gridRow >= emojiVisibleRows || col < 0 || col >= emojisPerRow*emojiCellWidth {
+		return nil
+	}
+	if i := (p.offset+gridRow)*emojisPerRow + col/emojiCellWidth; i < len(p.items) {
+		p.index = i
+		return p.pick()
+	}
+	return nil
+}
+
+// pick chooses the selected emoji, or none, and closes the picker
+func (p *EmojiPicker) pick() tea.Cmd {
+	p.Close()
+	if p.index < 0 || p.index >= len(p.items) {
+		p.value = ""
+		return nil
+	}
+	p.value = p.items[p.index]
+	picked := p.value
+	return func() tea.Msg { return EmojiPickedMsg{Emoji: picked} }
+}
+
+// refresh recomputes the grid for the search and skin tone, keeping the
+// same emoji selected where it's still there
+func (p *EmojiPicker) refresh() {
+	var selected string
+	if p.index >= 0 && p.index < len(p.items) {
+		selected = p.items[p.index]
+	}
+
+	searching := strings.TrimSpace(p.search.Value()) != ""
+	var items []string
+	if searching {
+		items = emoji.Search(p.search.Value())
+	} else {
+		items = append(slices.Clone(p.recents), emoji.Suggested...)
+	}
+
+	p.items = make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, e := range items {
+		// Recents keep the tone they were picked in
+		if searching || i >= len(p.recents) {
+			e = emoji.WithTone(e, p.tone)
+		}
+		if !seen[e] {
+			seen[e] = true
+			p.items = append(p.items, e)
+		}
+	}
+
+	if selected != "" {
+		p.index = slices.Index(p.items, emoji.WithTone(selected, p.tone))
+		if p.index < 0 {
+			p.index = slices.Index(p.items, selected)
+		}
+	}
+	p.follow()
+}
+
+// follow scrolls the grid just far enough to show the selected emoji
+func (p *EmojiPicker) follow() {
+	if p.index == -1 {
+		p.offset = 0
+		return
+	}
+	row := p.index / emojisPerRow
+	if row < p.offset {
+		p.offset = row
+	} else if row >= p.offset+emojiVisibleRows {
+		p.offset = row - emojiVisibleRows + 1
+	}
+	totalRows := (len(p.items) + emojisPerRow - 1) / emojisPerRow
+	p.offset = max(min(p.offset, totalRows-emojiVisibleRows), 0)
+}
+
+// viewTop renders the picker lines down to and including "(none)"
+func (p EmojiPicker) viewTop() string {
+	s := Title.Render("Pick an Emoji") + "\n\n"
+	s += "Search: " + p.search.View() + "\n\n"
+	if p.index == -1 {
+		s += "[(none)]\n"
+	} else {
+		s += " (none) \n"
+	}
+	return s
+}
+
+// viewHeader renders every picker line above the first grid row
+func (p EmojiPicker) viewHeader() string {
+	caption := "Recent & suggested"
+	if len(p.recents) == 0 {
+		caption = "Suggested"
+	}
+	if strings.TrimSpace(p.search.Value()) != "" {
+		caption = fmt.Sprintf("%d found", len(p.items))
+	}
+	tone := "tone " + p.tone.Swatch()
+	gap := max(emojisPerRow*emojiCellWidth-ansi.StringWidth(caption)-emoji.Width(tone), 1)
+	s := p.viewTop() + "\n" + MutedText.Render(caption+strings.Repeat(" ", gap)) + tone + "\n"
+
+	if p.offset > 0 && len(p.items) > 0 {
+		s += MutedText.Render("        ▲ more above ▲") + "\n"
+	}
+	return s
+}
+
+// View renders the picker as a modal box
+func (p EmojiPicker) View(keys KeyMap) string {
+	s := p.viewHeader()
+
+	if len(p.items) == 0 {
+		s += MutedText.Render("No emojis found") + "\n"
+	} else {
+		totalRows := (len(p.items) + emojisPerRow - 1) / emojisPerRow
+		end := min(p.offset+emojiVisibleRows, totalRows)
+		for row := p.offset; row < end; row++ {
+			var line strings.Builder
+			for col := range emojisPerRow {
+				i := row*emojisPerRow + col
+				if i >= len(p.items) {
+					break
+				}
+				left, right := " ", " "
+				if i == p.index {
+					left, right = "[", "]"
+				}
+				line.WriteString(left + p.items[i] + right)
+			}
+			s += line.String() + "\n"
+		}
+		if end < totalRows {
+			s += MutedText.Render("        ▼ more below ▼") + "\n"
+		}
+	}
+	s += "\n"
+
+	s += HelpLine(keys.PageUp, keys.PageDown) + "\n"
+	s += HelpLine(keys.SkinTone, keys.Select, keys.Back)
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Primary).
+		Background(lipgloss.Color("#1F2937")).
+		Padding(1, 2).
+		Width(54)
+
+	return FitEmojis(modalStyle.Render(s), 3)
+}
+
+// FitEmojis takes back the padding lipgloss gives too much of on lines of
+// a bordered box that hold emojis it measures narrower than emoji.Width, so
+// their right border lines up with the rest. edge is the cells of padding
+// and border after the content of each line.
+func FitEmojis(box string, edge int) string {
+	lines := strings.Split(box, "\n")
+	for i, line := range lines {
+		width := ansi.StringWidth(line)
+		extra := emoji.Width(line) - width
+		if extra <= 0 || extra > width-edge {
+			continue
+		}
+		lines[i] = ansi.Cut(line, 0, width-edge-extra) + ansi.Cut(line, width-edge, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Field renders the chosen emoji for the form field that opens the picker
+func (p EmojiPicker) Field(focused bool) string {
+	display := p.value
+	if display == "" {
+		display = "(none)"
+		if !focused {
+			return MutedText.Render(display)
+		}
+	}
+	if focused {
+		return SelectedItem.Render("[" + display + "]")
+	}
+	return display
+}
+
+// EmojiPickerHelp returns the help section for an open emoji picker
+func EmojiPickerHelp(keys KeyMap) HelpSection {
+	return HelpSection{Title: "Emoji Picker", Bindings: []key.Binding{
+		keys.Up, keys.Down, keys.Left, keys.Right,
+		keys.PageUp, keys.PageDown, keys.SkinTone, keys.Select, keys.Back,
+	}}
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vittolewerissa/hbt/internal/shared/emoji"
+)
+
+// typeKeys sends each key to the picker, returning the last command
+func typeKeys(p *EmojiPicker, keys ...tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+	for _, k := range keys {
+		cmd = p.Update(k, DefaultKeyMap)
+	}
+	return cmd
+}
+
+func TestEmojiPickerRecentsComeFirst(t *testing.T) {
+	p := NewEmojiPicker("")
+	p.SetPrefs([]string{"🏃🏽", "📚"}, emoji.ToneDark)
+	p.Open()
+
+	if p.index != -1 {
+		t.Errorf("index = %d with no emoji chosen, want -1 for (none)", p.index)
+	}
+	// Recents keep their own tone; suggestions take the picker's
+	if p.items[0] != "🏃🏽" || p.items[1] != "📚" {
+		t.Errorf("items start %q, want the recents", p.items[:2])
+	}
+	for _, e := range p.items[2:] {
+		if e == "📚" {
+			t.Error("a recent emoji is repeated among the suggestions")
+		}
+	}
+}
+
+func TestEmojiPickerSearchAndPick(t *testing.T) {
+	p := NewEmojiPicker("📚")
+	p.Open()
+	if p.items[p.index] != "📚" {
+		t.Errorf("opened on %q, want the chosen emoji", p.items[p.index])
+	}
+
+	typeKeys(&p, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("person run")})
+	cmd := typeKeys(&p, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if p.IsOpen() {
+		t.Error("picker still open after picking")
+	}
+	if p.Value() != "🏃" {
+		t.Errorf("Value() = %q, want the best match 🏃", p.Value())
+	}
+	if msg, ok := cmd().(EmojiPickedMsg); !ok || msg.Emoji != "🏃" {
+		t.Errorf("picking sent %#v, want EmojiPickedMsg for 🏃", cmd())
+	}
+}
+
+func TestEmojiPickerSkinTone(t *testing.T) {
+	p := NewEmojiPicker("")
+	p.Open()
+	typeKeys(&p, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("waving hand")})
+
+	cmd := typeKeys(&p, tea.KeyMsg{Type: tea.KeyCtrlT})
+	if msg, ok := cmd().(SkinToneChangedMsg); !ok || msg.Tone != emoji.ToneLight {
+		t.Errorf("tone key sent %#v, want SkinToneChangedMsg for the light tone", cmd())
+	}
+	if got := p.items[p.index]; got != "👋🏻" {
+		t.Errorf("selected %q after changing tone, want 👋🏻", got)
+	}
+}
+
+func TestEmojiPickerClick(t *testing.T) {
+	p := NewEmojiPicker("")
+	p.Open()
+
+	// Two cells into the third emoji of the first grid row
+	row := strings.Count(p.viewHeader(), "\n") + ModalContentRow
+	col := ModalContentCol + 2*emojiCellWidth + 1
+	p.Click(ModalClickMsg{Row: row, Col: col}, DefaultKeyMap)
+	if p.Value() != emoji.Suggested[2] {
+		t.Errorf("clicked %q, want %q", p.Value(), emoji.Suggested[2])
+	}
+
+	p.Open()
+	p.Click(ModalClickMsg{Row: -1, Col: 0}, DefaultKeyMap)
+	if p.IsOpen() || p.Value() != emoji.Suggested[2] {
+		t.Error("clicking outside should close the picker and keep the emoji")
+	}
+}
+
+func TestEmojiPickerBoxLinesUp(t *testing.T) {
+	p := NewEmojiPicker("")
+	p.SetPrefs([]string{"✌🏽", "#️⃣", "1️⃣"}, emoji.ToneMedium)
+	p.Open()
+
+	// Keycaps and tones of ✌️ are drawn two cells wide like other emojis
+	lines := strings.Split(p.View(DefaultKeyMap), "\n")
+	for i, line := range lines {
+		if got, want := emoji.Width(line), emoji.Width(lines[0]); got != want {
+			t.Errorf("line %d is %d cells wide, want %d: %q", i, got, want, line)
+		}
+	}
+}
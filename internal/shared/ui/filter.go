@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FilterChip is the style for an active filter chip
+var FilterChip = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FFFFFF")).
+	Background(Primary).
+	Padding(0, 1)
+
+// FilterBar is the "/" filter prompt shown above a list. The query stays
+// set after editing ends so the list remains filtered.
+type FilterBar struct {
+	input   textinput.Model
+	editing bool
+}
+
+// NewFilterBar creates an empty filter bar
+func NewFilterBar() FilterBar {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "name, category, is:due, is:todo, category:x"
+	input.CharLimit = 100
+	input.Width = 24
+	return FilterBar{input: input}
+}
+
+// SetPlaceholder replaces the hint shown while the query is empty
+func (b *FilterBar) SetPlaceholder(placeholder string) {
+	b.input.Placeholder = placeholder
+}
+
+// Editing returns true while the filter query has focus
+func (b FilterBar) Editing() bool {
+	return b.editing
+}
+
+// Query returns the current filter query
+func (b FilterBar) Query() string {
+	return b.input.Value()
+}
+
+// SetQuery replaces the filter query without starting to edit it
+func (b *FilterBar) SetQuery(query string) {
+	b.input.SetValue(query)
+}
+
+// Open focuses the filter query for editing
+func (b *FilterBar) Open() tea.Cmd {
+	b.editing = true
+	b.input.CursorEnd()
+	return b.input.Focus()
+}
+
+// Update handles a key while editing. Select keeps the query and leaves
+// editing, Back clears it. It reports whether the query changed.
+func (b *FilterBar) Update(msg tea.KeyMsg, keys KeyMap) (bool, tea.Cmd) {
+	before := b.input.Value()
+
+	switch {
+	case MatchesNonText(msg, keys.Select):
+		b.editing = false
+		b.input.Blur()
+		return false, nil
+	case MatchesNonText(msg, keys.Back):
+		b.editing = false
+		b.input.Blur()
+		b.input.SetValue("")
+		return before != "", nil
+	}
+
+	var cmd tea.Cmd
+	b.input, cmd = b.input.Update(msg)
+	return b.input.Value() != before, cmd
+}
+
+// View renders the prompt and chips, or nothing when no filter is set.
+// shown and total describe how many list items pass the filter.
+func (b FilterBar) View(chips []string, shown, total int) string {
+	if !b.editing && b.input.Value() == "" {
+		return ""
+	}
+
+	s := b.input.View()
+	if !b.editing {
+		s = MutedText.Render("/" + b.input.Value())
+	}
+	s += "  " + MutedText.Render(fmt.Sprintf("%d/%d", shown, total))
+
+	if len(chips) > 0 {
+		s += "\n"
+		for i, chip := range chips {
+			if i > 0 {
+				s += " "
+			}
+			s += FilterChip.Render(chip)
+		}
+	}
+	return s + "\n"
+}
+
+// FilterHelp returns the bindings that apply while editing a filter
+func FilterHelp(keys KeyMap) []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys(keys.Select.Keys()...), key.WithHelp(keys.Select.Help().Key, "apply filter")),
+		key.NewBinding(key.WithKeys(keys.Back.Keys()...), key.WithHelp(keys.Back.Help().Key, "clear filter")),
+	}
+}
@@ -0,0 +1,294 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMapPath returns the location of the user's key binding overrides
+func KeyMapPath() string {
+	// Try XDG_CONFIG_HOME first, then fall back to ~/.config
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "hbt", "keys.toml")
+}
+
+// bindingNames maps the action names used in keys.toml to key map fields
+var bindingNames = map[string]func(*KeyMap) *key.Binding{
+	"up":           func(k *KeyMap) *key.Binding { return &k.Up },
+	"down":         func(k *KeyMap) *key.Binding { return &k.Down },
+	"left":         func(k *KeyMap) *key.Binding { return &k.Left },
+	"right":        func(k *KeyMap) *key.Binding { return &k.Right },
+	"page_up":      func(k *KeyMap) *key.Binding { return &k.PageUp },
+	"page_down":    func(k *KeyMap) *key.Binding { return &k.PageDown },
+	"top":          func(k *KeyMap) *key.Binding { return &k.Top },
+	"bottom":       func(k *KeyMap) *key.Binding { return &k.Bottom },
+	"next_tab":     func(k *KeyMap) *key.Binding { return &k.NextTab },
+	"prev_tab":     func(k *KeyMap) *key.Binding { return &k.PrevTab },
+	"select":       func(k *KeyMap) *key.Binding { return &k.Select },
+	"toggle":       func(k *KeyMap) *key.Binding { return &k.Toggle },
+	"add":          func(k *KeyMap) *key.Binding { return &k.Add },
+	"edit":         func(k *KeyMap) *key.Binding { return &k.Edit },
+	"delete":       func(k *KeyMap) *key.Binding { return &k.Delete },
+	"filter":       func(k *KeyMap) *key.Binding { return &k.Filter },
+	"collapse":     func(k *KeyMap) *key.Binding { return &k.Collapse },
+	"move_up":      func(k *KeyMap) *key.Binding { return &k.MoveUp },
+	"move_down":    func(k *KeyMap) *key.Binding { return &k.MoveDown },
+	"sort":         func(k *KeyMap) *key.Binding { return &k.Sort },
+	"archive":      func(k *KeyMap) *key.Binding { return &k.Archive },
+	"restore":      func(k *KeyMap) *key.Binding { return &k.Restore },
+	"note":         func(k *KeyMap) *key.Binding { return &k.Note },
+	"journal":      func(k *KeyMap) *key.Binding { return &k.Journal },
+	"check_in":     func(k *KeyMap) *key.Binding { return &k.CheckIn },
+	"back":         func(k *KeyMap) *key.Binding { return &k.Back },
+	"confirm":      func(k *KeyMap) *key.Binding { return &k.Confirm },
+	"cancel":       func(k *KeyMap) *key.Binding { return &k.Cancel },
+	"next_field":   func(k *KeyMap) *key.Binding { return &k.NextField },
+	"prev_field":   func(k *KeyMap) *key.Binding { return &k.PrevField },
+	"save":         func(k *KeyMap) *key.Binding { return &k.Save },
+	"clear":        func(k *KeyMap) *key.Binding { return &k.Clear },
+	"editor":       func(k *KeyMap) *key.Binding { return &k.Editor },
+	"skin_tone":    func(k *KeyMap) *key.Binding { return &k.SkinTone },
+	"undo":         func(k *KeyMap) *key.Binding { return &k.Undo },
+	"redo":         func(k *KeyMap) *key.Binding { return &k.Redo },
+	"toggle_stats": func(k *KeyMap) *key.Binding { return &k.ToggleStats },
+	"stats_view":   func(k *KeyMap) *key.Binding { return &k.StatsView },
+	"focus_stats":  func(k *KeyMap) *key.Binding { return &k.FocusStats },
+	"shrink_panel": func(k *KeyMap) *key.Binding { return &k.ShrinkPanel },
+	"grow_panel":   func(k *KeyMap) *key.Binding { return &k.GrowPanel },
+	"help":         func(k *KeyMap) *key.Binding { return &k.Help },
+	"quit":         func(k *KeyMap) *key.Binding { return &k.Quit },
+}
+
+// keyContexts lists the actions that are live at the same time. A key may
+// be bound to at most one action within a context.
+var keyContexts = map[string][]string{
+	"list": {
+		"up", "down", "left", "right", "page_up", "page_down", "top", "bottom",
+		"next_tab", "prev_tab", "toggle", "add", "edit", "delete", "filter", "collapse",
+		"move_up", "move_down", "sort", "archive", "restore", "note", "journal", "check_in",
+		"undo", "redo", "toggle_stats", "stats_view", "focus_stats", "shrink_panel", "grow_panel",
+		"help", "quit",
+	},
+	"form": {
+		"next_field", "prev_field", "left", "right", "select", "save", "clear", "editor", "back",
+	},
+	"modal": {
+		"up", "down", "left", "right", "page_up", "page_down", "select", "skin_tone", "back",
+	},
+	"confirm": {
+		"confirm", "cancel", "back",
+	},
+}
+
+// LoadKeyMap returns the default key map with any overrides from the file at
+// path applied. A missing file is not an error. If the file can't be parsed
+// or produces conflicting bindings, the defaults are returned with the error.
+func LoadKeyMap(path string) (KeyMap, error) {
+	keys := DefaultKeyMap
+	if path == "" {
+		return keys, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return keys, nil
+	}
+	if err != nil {
+		return keys, fmt.Errorf("failed to open key bindings: %w", err)
+	}
+	defer f.Close()
+
+	overrides, err := parseKeyFile(f)
+	if err != nil {
+		return keys, fmt.Errorf("%s: %w", path, err)
+	}
+
+	custom := DefaultKeyMap
+	for name, bound := range overrides {
+		field, ok := bindingNames[name]
+		if !ok {
+			return keys, fmt.Errorf("%s: unknown action %q", path, name)
+		}
+		b := field(&custom)
+		if len(bound) == 0 {
+			b.SetEnabled(false)
+			continue
+		}
+		*b = key.NewBinding(
+			key.WithKeys(bound...),
+			key.WithHelp(helpKeyLabel(bound), b.Help().Desc),
+		)
+	}
+
+	if err := custom.Validate(); err != nil {
+		return keys, fmt.Errorf("%s: %w", path, err)
+	}
+	return custom, nil
+}
+
+// Validate reports keys that are bound to more than one action in the same context
+func (k KeyMap) Validate() error {
+	contexts := make([]string, 0, len(keyContexts))
+	for name := range keyContexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+
+	for _, ctx := range contexts {
+		owner := make(map[string]string)
+		for _, action := range keyContexts[ctx] {
+			b := bindingNames[action](&k)
+			if !b.Enabled() {
+				continue
+			}
+			for _, kk := range b.Keys() {
+				if prev, ok := owner[kk]; ok && prev != action {
+					return fmt.Errorf("key %q is bound to both %s and %s in %s context", kk, prev, action, ctx)
+				}
+				owner[kk] = action
+			}
+		}
+	}
+	return nil
+}
+
+// parseKeyFile reads the small TOML subset used by keys.toml:
+//
+//	# comment
+//	[keys]
+//	up = ["up", "k"]
+//	quit = "ctrl+q"
+//	add = []          # unbind
+func parseKeyFile(f *os.File) (map[string][]string, error) {
+	overrides := make(map[string][]string)
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if line != "[keys]" {
+				return nil, fmt.Errorf("line %d: unknown section %s", lineNo, line)
+			}
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected action = keys", lineNo)
+		}
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+
+		bound, err := parseKeyValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		overrides[name] = bound
+	}
+	return overrides, scanner.Err()
+}
+
+// parseKeyValue parses either a quoted string or an array of quoted strings
+func parseKeyValue(value string) ([]string, error) {
+	if !strings.HasPrefix(value, "[") {
+		s, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s", value)
+		}
+		return []string{s}, nil
+	}
+
+	if !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("unterminated array %s", value)
+	}
+	inner := strings.TrimSpace(value[1 : len(value)-1])
+	if inner == "" {
+		return nil, nil
+	}
+
+	var keys []string
+	for _, part := range splitArray(inner) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		s, err := strconv.Unquote(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s", part)
+		}
+		keys = append(keys, s)
+	}
+	return keys, nil
+}
+
+// splitArray splits the inside of an array on the commas that aren't
+// inside quotes
+func splitArray(inner string) []string {
+	var parts []string
+	inQuote, escaped := false, false
+	start := 0
+	for i, r := range inner {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuote && r == '\\':
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+		case r == ',' && !inQuote:
+			parts = append(parts, inner[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, inner[start:])
+}
+
+// stripComment removes a trailing # comment that isn't inside quotes. A
+// backslash inside quotes escapes the next character, as in the values.
+func stripComment(line string) string {
+	inQuote, escaped := false, false
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuote && r == '\\':
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+		case r == '#' && !inQuote:
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// helpKeyLabel builds the short key label shown in help, e.g. "up/k"
+func helpKeyLabel(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		if k == " " {
+			k = "space"
+		}
+		labels[i] = k
+	}
+	return strings.Join(labels, "/")
+}
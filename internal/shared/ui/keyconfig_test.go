@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStripComment(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`up = "k" # vim`, `up = "k" `},
+		{`up = "#"`, `up = "#"`},
+		{`up = "\""  # the quote key`, `up = "\""  `},
+		{`up = ["\"", "#"] # both`, `up = ["\"", "#"] `},
+		{`up = "\\" # backslash`, `up = "\\" `},
+		{`# only a comment`, ``},
+	}
+	for _, tt := range tests {
+		if got := stripComment(tt.line); got != tt.want {
+			t.Errorf("stripComment(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseKeyValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{`"k"`, []string{"k"}},
+		{`["k", "up"]`, []string{"k", "up"}},
+		{`[",", "n"]`, []string{",", "n"}},
+		{`["\"", ","]`, []string{`"`, ","}},
+		{`[]`, nil},
+	}
+	for _, tt := range tests {
+		got, err := parseKeyValue(tt.value)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("parseKeyValue(%s) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{`k`, `["k"`, `["k", n]`} {
+		if _, err := parseKeyValue(value); err == nil {
+			t.Errorf("parseKeyValue(%s) accepted an invalid value", value)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package ui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
 
 // KeyMap defines all key bindings for the application
 type KeyMap struct {
@@ -9,6 +13,10 @@ type KeyMap struct {
 	Down     key.Binding
 	Left     key.Binding
 	Right    key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Top      key.Binding
+	Bottom   key.Binding
 	NextTab  key.Binding
 	PrevTab  key.Binding
 
@@ -18,13 +26,38 @@ type KeyMap struct {
 	Add      key.Binding
 	Edit     key.Binding
 	Delete   key.Binding
+	Filter   key.Binding
+	Collapse key.Binding
+	MoveUp   key.Binding
+	MoveDown key.Binding
+	Sort     key.Binding
+	Archive  key.Binding
+	Restore  key.Binding
+	Note     key.Binding
+	Journal  key.Binding
+	CheckIn  key.Binding
 	Back     key.Binding
 	Confirm  key.Binding
 	Cancel   key.Binding
 
+	// Forms
+	NextField key.Binding
+	PrevField key.Binding
+	Save      key.Binding
+	Clear     key.Binding
+	Editor    key.Binding
+	SkinTone  key.Binding
+
 	// App
-	Help key.Binding
-	Quit key.Binding
+	Undo        key.Binding
+	Redo        key.Binding
+	ToggleStats key.Binding
+	StatsView   key.Binding
+	FocusStats  key.Binding
+	ShrinkPanel key.Binding
+	GrowPanel   key.Binding
+	Help        key.Binding
+	Quit        key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings
@@ -45,6 +78,22 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("right", "l"),
 		key.WithHelp("right/l", "move right"),
 	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "page up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown"),
+		key.WithHelp("pgdown", "page down"),
+	),
+	Top: key.NewBinding(
+		key.WithKeys("g", "home"),
+		key.WithHelp("g/home", "go to top"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("G", "end"),
+		key.WithHelp("G/end", "go to bottom"),
+	),
 	NextTab: key.NewBinding(
 		key.WithKeys("tab"),
 		key.WithHelp("tab", "next tab"),
@@ -73,6 +122,46 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("d"),
 		key.WithHelp("d", "delete"),
 	),
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter"),
+	),
+	Collapse: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "collapse section"),
+	),
+	MoveUp: key.NewBinding(
+		key.WithKeys("K"),
+		key.WithHelp("K", "move item up"),
+	),
+	MoveDown: key.NewBinding(
+		key.WithKeys("J"),
+		key.WithHelp("J", "move item down"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "change sort order"),
+	),
+	Archive: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "archived habits"),
+	),
+	Restore: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "restore"),
+	),
+	Note: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "complete with note"),
+	),
+	Journal: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "journal"),
+	),
+	CheckIn: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "mood check-in"),
+	),
 	Back: key.NewBinding(
 		key.WithKeys("esc"),
 		key.WithHelp("esc", "back"),
@@ -85,9 +174,61 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("n"),
 		key.WithHelp("n", "cancel"),
 	),
+	NextField: key.NewBinding(
+		key.WithKeys("tab", "down"),
+		key.WithHelp("tab", "next field"),
+	),
+	PrevField: key.NewBinding(
+		key.WithKeys("shift+tab", "up"),
+		key.WithHelp("shift+tab", "previous field"),
+	),
+	Save: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("ctrl+s", "save"),
+	),
+	Clear: key.NewBinding(
+		key.WithKeys("backspace", "delete"),
+		key.WithHelp("backspace", "clear"),
+	),
+	Editor: key.NewBinding(
+		key.WithKeys("ctrl+e"),
+		key.WithHelp("ctrl+e", "open in editor"),
+	),
+	SkinTone: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("ctrl+t", "skin tone"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo"),
+	),
+	Redo: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "redo"),
+	),
+	ToggleStats: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "show/hide stats"),
+	),
+	StatsView: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "next stats view"),
+	),
+	FocusStats: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "focus stats panel"),
+	),
+	ShrinkPanel: key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "narrow tab panel"),
+	),
+	GrowPanel: key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "widen tab panel"),
+	),
 	Help: key.NewBinding(
-		key.WithKeys("?"),
-		key.WithHelp("?", "help"),
+		key.WithKeys("?", "f1"),
+		key.WithHelp("?/f1", "help"),
 	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c"),
@@ -103,10 +244,75 @@ func (k KeyMap) ShortHelp() []key.Binding {
 // FullHelp returns the full help for the key map
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Left, k.Right},
+		{k.Up, k.Down, k.Left, k.Right, k.PageUp, k.PageDown, k.Top, k.Bottom},
 		{k.NextTab, k.PrevTab},
-		{k.Select, k.Add, k.Edit, k.Delete},
+		{k.Toggle, k.Select, k.Add, k.Edit, k.Delete, k.Filter, k.Collapse},
+		{k.MoveUp, k.MoveDown, k.Sort, k.Archive, k.Restore, k.Note, k.Journal, k.CheckIn},
 		{k.Back, k.Confirm, k.Cancel},
-		{k.Quit},
+		{k.NextField, k.PrevField, k.Save, k.Clear, k.Editor, k.SkinTone},
+		{k.Undo, k.Redo, k.ToggleStats, k.StatsView, k.FocusStats, k.ShrinkPanel, k.GrowPanel, k.Help, k.Quit},
+	}
+}
+
+// MatchesNonText reports whether msg matches the binding and is not plain
+// typed text. Used while a text input has focus so that bindings on letters
+// don't swallow keystrokes meant for the input.
+func MatchesNonText(msg tea.KeyMsg, b key.Binding) bool {
+	if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+		return false
+	}
+	return key.Matches(msg, b)
+}
+
+// HelpLine renders bindings as a compact "key: desc  key: desc" hint line
+func HelpLine(bindings ...key.Binding) string {
+	var s string
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		if s != "" {
+			s += "  "
+		}
+		s += b.Help().Key + ": " + b.Help().Desc
 	}
+	return MutedText.Render(s)
+}
+
+// HelpSection is a titled group of bindings shown in the help overlay
+type HelpSection struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// HelpOverlay renders help sections as a modal box for the help overlay
+func HelpOverlay(title string, sections []HelpSection) string {
+	var s string
+	s += Title.Render(title) + "\n"
+
+	for _, section := range sections {
+		var rows string
+		for _, b := range section.Bindings {
+			if !b.Enabled() {
+				continue
+			}
+			rows += "\n" + lipgloss.NewStyle().Foreground(Primary).Width(14).Render(b.Help().Key) +
+				b.Help().Desc
+		}
+		if rows == "" {
+			continue
+		}
+		s += "\n" + lipgloss.NewStyle().Bold(true).Render(section.Title) + rows + "\n"
+	}
+
+	s += "\n" + MutedText.Render("press any key to close")
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Primary).
+		Background(lipgloss.Color("#1F2937")).
+		Padding(1, 2).
+		Width(48)
+
+	return modalStyle.Render(s)
 }
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ScrollList tracks the cursor and scroll position of a list that may be
+// taller than the space it's drawn in. Items and rows are counted
+// separately so lists can mix section headers and multi-line items.
+type ScrollList struct {
+	Cursor int // index of the selected item
+	Offset int // index of the first visible row
+	Height int // rows available for the list, including the scroll indicator
+}
+
+// HandleKey moves the cursor for navigation keys and reports whether the key was used
+func (l *ScrollList) HandleKey(msg tea.KeyMsg, keys KeyMap, count int) bool {
+	switch {
+	case key.Matches(msg, keys.Up):
+		l.Move(-1, count)
+	case key.Matches(msg, keys.Down):
+		l.Move(1, count)
+	case key.Matches(msg, keys.PageUp):
+		l.Move(-l.pageSize(), count)
+	case key.Matches(msg, keys.PageDown):
+		l.Move(l.pageSize(), count)
+	case key.Matches(msg, keys.Top):
+		l.Move(-count, count)
+	case key.Matches(msg, keys.Bottom):
+		l.Move(count, count)
+	default:
+		return false
+	}
+	return true
+}
+
+// Move shifts the cursor by delta items, clamped to a list of count items
+func (l *ScrollList) Move(delta, count int) {
+	l.Cursor += delta
+	l.Clamp(count)
+}
+
+// Clamp keeps the cursor inside a list of count items
+func (l *ScrollList) Clamp(count int) {
+	if l.Cursor >= count {
+		l.Cursor = count - 1
+	}
+	if l.Cursor < 0 {
+		l.Cursor = 0
+	}
+}
+
+// Follow scrolls just far enough that rows first through last (usually the
+// selected item) are visible in a list of total rows
+func (l *ScrollList) Follow(first, last, total int) {
+	visible := l.visibleRows(total)
+	if first < l.Offset {
+		l.Offset = first
+	}
+	if last >= l.Offset+visible {
+		l.Offset = last - visible + 1
+	}
+	l.clampOffset(total)
+}
+
+// Render returns the visible rows, each followed by a newline, plus a scroll
+// indicator line when the list doesn't fit
+func (l ScrollList) Render(rows []string) string {
+	visible := l.visibleRows(len(rows))
+	l.clampOffset(len(rows))
+
+	var s string
+	end := l.Offset + visible
+	for _, row := range rows[l.Offset:end] {
+		s += row + "\n"
+	}
+
+	if visible < len(rows) {
+		s += MutedText.Render(fmt.Sprintf("▲ %d more  ▼ %d more", l.Offset, len(rows)-end)) + "\n"
+	}
+	return s
+}
+
+// RowAt maps a line of the rendered list to an index into its rows, or -1
+// for the scroll indicator and lines past the end
+func (l ScrollList) RowAt(line, total int) int {
+	visible := l.visibleRows(total)
+	l.clampOffset(total)
+	if line < 0 || line >= visible {
+		return -1
+	}
+	return l.Offset + line
+}
+
+// visibleRows returns how many list rows fit, reserving a line for the
+// indicator when the list overflows
+func (l ScrollList) visibleRows(total int) int {
+	if l.Height <= 0 || total <= l.Height {
+		return total
+	}
+	if l.Height == 1 {
+		return 1
+	}
+	return l.Height - 1
+}
+
+func (l *ScrollList) clampOffset(total int) {
+	maxOffset := total - l.visibleRows(total)
+	if l.Offset > maxOffset {
+		l.Offset = maxOffset
+	}
+	if l.Offset < 0 {
+		l.Offset = 0
+	}
+}
+
+func (l ScrollList) pageSize() int {
+	if l.Height > 2 {
+		return l.Height - 2
+	}
+	return 1
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+)
+
+// Loader runs one kind of load for a tab, cancelling the load still in
+// flight when a newer one starts, since its result would be out of date
+type Loader struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// Start returns the context for a new load, which times out after
+// db.Timeout. Call the returned func once the load is done.
+func (l *Loader) Start() (context.Context, context.CancelFunc) {
+	ctx, cancel := db.WithTimeout(context.Background())
+	l.mu.Lock()
+	if l.cancel != nil {
+		l.cancel()
+	}
+	l.cancel = cancel
+	l.mu.Unlock()
+	return ctx, cancel
+}
+
+// Superseded reports whether a load failed only because a newer one
+// replaced it, so its error isn't worth showing
+func Superseded(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
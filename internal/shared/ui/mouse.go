@@ -0,0 +1,28 @@
+package ui
+
+// ClickMsg reports a left click inside the active tab's panel. Row and Col
+// are relative to the first line and column of the panel content.
+type ClickMsg struct {
+	Row int
+	Col int
+}
+
+// ModalClickMsg reports a left click inside an open modal. Row and Col are
+// relative to the top-left corner of the modal box, including its border.
+type ModalClickMsg struct {
+	Row int
+	Col int
+}
+
+// ScrollMsg reports mouse wheel movement over the active tab or its modal.
+// Delta is -1 for up and 1 for down.
+type ScrollMsg struct {
+	Delta int
+}
+
+// Modal boxes are drawn with a one-cell border and Padding(1, 2), so their
+// content starts this far from the box's top-left corner.
+const (
+	ModalContentRow = 2
+	ModalContentCol = 3
+)
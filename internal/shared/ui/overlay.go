@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rivo/uniseg"
+	"github.com/vittolewerissa/hbt/internal/shared/emoji"
+)
+
+// ModalShadow styles the cells a modal's drop shadow falls on
+var ModalShadow = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#1F2937")).
+	Background(lipgloss.Color("#030712"))
+
+// Layer is a box drawn over the screen with its top-left corner at Row and
+// Col. A layer with Shadow casts a drop shadow down and to the right.
+type Layer struct {
+	Content string
+	Row     int
+	Col     int
+	Shadow  bool
+}
+
+// Centered returns a layer that puts box in the middle of base
+func Centered(base, box string) Layer {
+	baseWidth, baseHeight := lipgloss.Size(base)
+	boxWidth, boxHeight := lipgloss.Size(box)
+	return Layer{
+		Content: box,
+		Row:     max((baseHeight-boxHeight)/2, 0),
+		Col:     max((baseWidth-boxWidth)/2, 0),
+	}
+}
+
+// Composite draws layers over base in order, so later layers sit on top of
+// earlier ones. Lines are cut by display cell: a wide character under the
+// edge of a layer is replaced with spaces instead of shifting the rest of
+// its row, and the base's colors carry on after the layer as they were.
+// Layers are clipped to the size of base.
+func Composite(base string, layers ...Layer) string {
+	lines := strings.Split(base, "\n")
+	screenWidth := lipgloss.Width(base)
+
+	for _, l := range layers {
+		box := strings.Split(l.Content, "\n")
+		width := 0
+		for _, line := range box {
+			width = max(width, emoji.Width(line))
+		}
+
+		// The shadow is one row down and two cells right, which looks
+		// about square with cells twice as tall as they are wide
+		if l.Shadow {
+			for i := range box {
+				row := l.Row + i + 1
+				if row < 0 || row >= len(lines) {
+					continue
+				}
+				from, to := clip(l.Col+2, l.Col+2+width, screenWidth)
+				if from < to {
+					left, under, right := splitCells(lines[row], from, to)
+					lines[row] = left + ModalShadow.Render(ansi.Strip(under)) + right
+				}
+			}
+		}
+
+		for i, boxLine := range box {
+			row := l.Row + i
+			if row < 0 || row >= len(lines) {
+				continue
+			}
+			from, to := clip(l.Col, l.Col+width, screenWidth)
+			if from >= to {
+				continue
+			}
+			// Pad short lines so the box covers the same cells on every row,
+			// and cut off what runs past the right of the screen
+			boxLine = emoji.Pad(boxLine, width)
+			_, boxLine, _ = splitCells(boxLine, from-l.Col, to-l.Col)
+			left, _, right := splitCells(lines[row], from, to)
+			lines[row] = left + boxLine + right
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// clip limits the cells from..to to the screen
+func clip(from, to, width int) (int, int) {
+	return max(from, 0), min(to, width)
+}
+
+// splitCells splits line into the cells before from, the cells from up to
+// to, and the cells after. The first two parts are padded with spaces to
+// their full width. Each part starts with the colors in effect where it
+// begins in line and resets them at its end, so parts can be rejoined with
+// something else in between. A wide character cut by from or to becomes a
+// space on each side of the cut.
+func splitCells(line string, from, to int) (left, middle, right string) {
+	var parts [3]strings.Builder
+	var applied [3]string // the colors each part has been written with
+	var style string      // the colors in effect at this point in line
+
+	partAt := func(col int) int {
+		switch {
+		case col < from:
+			return 0
+		case col < to:
+			return 1
+		}
+		return 2
+	}
+	write := func(p int, s string) {
+		if applied[p] != style {
+			if applied[p] != "" {
+				parts[p].WriteString(ansi.ResetStyle)
+			}
+			parts[p].WriteString(style)
+			applied[p] = style
+		}
+		parts[p].WriteString(s)
+	}
+
+	col := 0
+	var state byte
+	for len(line) > 0 {
+		seq, width, n, newState := ansi.DecodeSequence(line, state, nil)
+		if width > 0 {
+			// Take the whole character, which ansi stops short of after a
+			// plain one such as the digit of a keycap, at its emoji width
+			seq, _, _, _ = uniseg.FirstGraphemeClusterInString(line, -1)
+			n, width = len(seq), emoji.Width(seq)
+		}
+		line, state = line[n:], newState
+
+		if width == 0 {
+			switch {
+			case isReset(seq):
+				style = ""
+			case strings.HasPrefix(seq, "\x1b[0;") && isSGR(seq):
+				// Resets, then sets what follows
+				style = seq
+			case isSGR(seq):
+				style += seq
+			default:
+				parts[partAt(col)].WriteString(seq)
+			}
+			continue
+		}
+
+		first, last := partAt(col), partAt(col+width-1)
+		if first == last {
+			write(first, seq)
+		} else {
+			for c := col; c < col+width; c++ {
+				write(partAt(c), " ")
+			}
+		}
+		col += width
+	}
+
+	for p := range parts {
+		if applied[p] != "" {
+			parts[p].WriteString(ansi.ResetStyle)
+		}
+	}
+	parts[0].WriteString(strings.Repeat(" ", max(from-col, 0)))
+	parts[1].WriteString(strings.Repeat(" ", max(to-max(col, from), 0)))
+	return parts[0].String(), parts[1].String(), parts[2].String()
+}
+
+// isSGR reports whether seq sets colors or text attributes
+func isSGR(seq string) bool {
+	return strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m")
+}
+
+// isReset reports whether seq clears every color and attribute
+func isReset(seq string) bool {
+	return seq == "\x1b[m" || seq == "\x1b[0m"
+}
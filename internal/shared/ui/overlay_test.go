@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestComposite(t *testing.T) {
+	tests := []struct {
+		name   string
+		base   string
+		layers []Layer
+		want   string
+	}{
+		{
+			name:   "plain text",
+			base:   "abcdefgh\nijklmnop",
+			layers: []Layer{{Content: "XY", Row: 1, Col: 3}},
+			want:   "abcdefgh\nijkXYnop",
+		},
+		{
+			name:   "wide characters under the edges",
+			base:   "ab🧘cd🏃ef",
+			layers: []Layer{{Content: "WXYZ", Row: 0, Col: 3}},
+			want:   "ab WXYZ ef",
+		},
+		{
+			name:   "wide characters inside the box",
+			base:   "abcdefgh",
+			layers: []Layer{{Content: "💪X", Row: 0, Col: 2}},
+			want:   "ab💪Xfgh",
+		},
+		{
+			name:   "keycaps inside the box",
+			base:   "abcdefgh",
+			layers: []Layer{{Content: "1️⃣X", Row: 0, Col: 2}},
+			want:   "ab1️⃣Xfgh",
+		},
+		{
+			name:   "short lines are padded up to the box",
+			base:   "abcdefgh\nab\n",
+			layers: []Layer{{Content: "XY\nZ", Row: 1, Col: 4}},
+			want:   "abcdefgh\nab  XY\n    Z ",
+		},
+		{
+			name:   "clipped to the screen",
+			base:   "abcdef\nghijkl",
+			layers: []Layer{{Content: "XYZ\nXYZ\nXYZ", Row: 1, Col: 4}},
+			want:   "abcdef\nghijXY",
+		},
+		{
+			name: "stacked",
+			base: "abcdefgh",
+			layers: []Layer{
+				{Content: "XXXX", Row: 0, Col: 1},
+				{Content: "🧘", Row: 0, Col: 2},
+			},
+			want: "aX🧘Xfgh",
+		},
+		{
+			name:   "shadow keeps the text beneath",
+			base:   "abcdef\nghijkl\nmnopqr",
+			layers: []Layer{{Content: "XY", Row: 0, Col: 1, Shadow: true}},
+			want:   "aXYdef\nghijkl\nmnopqr",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ansi.Strip(Composite(tt.base, tt.layers...)); got != tt.want {
+				t.Errorf("Composite() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompositeRestoresColors(t *testing.T) {
+	red, blue := "\x1b[31m", "\x1b[34m"
+	base := red + "aaaa" + blue + "bbbb" + ansi.ResetStyle + "cccc"
+
+	got := Composite(base, Layer{Content: "\x1b[1mXX" + ansi.ResetStyle, Col: 3})
+	want := red + "aaa" + ansi.ResetStyle +
+		"\x1b[1mXX" + ansi.ResetStyle +
+		red + blue + "bbb" + ansi.ResetStyle + "cccc"
+	if got != want {
+		t.Errorf("Composite() = %q, want %q", got, want)
+	}
+
+	// Nothing of the box's style leaks into the rest of the line
+	if after := got[strings.LastIndex(got, "XX"):]; strings.Contains(after, "\x1b[1m") {
+		t.Errorf("bold carried on after the box: %q", after)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/shared/emoji"
 )
 
 // Colors
@@ -126,7 +127,7 @@ func TitledPanel(title, content string, width, height int) string {
 
 	// Build top border with title
 	titleText := " " + title + " "
-	titleLen := len(titleText)
+	titleLen := lipgloss.Width(titleText)
 	remainingWidth := width - 2 - titleLen - 1 // -2 for corners, -1 for initial dash
 	if remainingWidth < 0 {
 		remainingWidth = 0
@@ -148,7 +149,7 @@ func TitledPanel(title, content string, width, height int) string {
 	var middle string
 	for _, line := range contentLines {
 		// Pad line to fill width
-		lineWidth := lipgloss.Width(line)
+		lineWidth := emoji.Width(line)
 		padding := width - 2 - lineWidth // -2 for side borders
 		if padding < 0 {
 			padding = 0
@@ -14,6 +14,7 @@ type BarChart struct {
 	MaxValue  float64
 	BarChar   rune
 	EmptyChar rune
+	Color     string // hex color for the bar; without one it's colored by value
 }
 
 // NewBarChart creates a new bar chart
@@ -53,9 +54,11 @@ func (c *BarChart) Render(value float64, label string) string {
 	filled := strings.Repeat(string(c.BarChar), filledWidth)
 	empty := strings.Repeat(string(c.EmptyChar), emptyWidth)
 
-	// Color the bar based on value
+	// Color the bar in its own color, or based on value
 	var barStyle lipgloss.Style
 	switch {
+	case c.Color != "":
+		barStyle = ui.CategoryStyle(lipgloss.NewStyle().Foreground(ui.Success), c.Color)
 	case ratio >= 0.8:
 		barStyle = lipgloss.NewStyle().Foreground(ui.Success)
 	case ratio >= 0.5:
@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+)
+
+// heatmapWeeks is how far back the activity heatmap can go
+const heatmapWeeks = 26
+
+// HeatmapWidth is the width of the activity heatmap panel: a date label and
+// a two-cell square for each day of the week, inside the panel's border
+const HeatmapWidth = 30
+
+// heatmapLevels are the squares for a day's completion rate, from none to
+// all, shaded so they still read without color
+var heatmapLevels = []string{"░", "▒", "▓", "█"}
+
+// heatmapSquare returns the square for a day's daily habits, in the color
+// of the category most of them were in
+func heatmapSquare(stat DailyStats) string {
+	if stat.Total == 0 {
+		return ui.MutedText.Render("·")
+	}
+	if stat.Completed == 0 {
+		return ui.MutedText.Render(heatmapLevels[0])
+	}
+	level := 1 + (len(heatmapLevels)-2)*stat.Completed/stat.Total
+	return ui.CategoryStyle(lipgloss.NewStyle().Foreground(ui.Success), stat.Color).Render(heatmapLevels[level])
+}
+
+// RenderHeatmap renders the share of daily habits done each day, a week to
+// a row with the current week at the top, in as many weeks as fit
+func (m Model) RenderHeatmap(width, height int) string {
+	if m.err != nil || m.overview == nil {
+		return ui.TitledPanel("Activity", "", width, height)
+	}
+	if len(m.history) == 0 {
+		return ui.TitledPanel("Activity", ui.MutedText.Render("No daily habits yet"), width, height)
+	}
+
+	byDate := make(map[string]DailyStats, len(m.history))
+	for _, stat := range m.history {
+		byDate[stat.Date.Format("2006-01-02")] = stat
+	}
+	today := m.history[0].Date
+	monday := today.AddDate(0, 0, -daysSinceMonday(today))
+
+	// Leave room for the panel's border and padding, the day header and
+	// the legend
+	weeks := min(heatmapWeeks, height-3-1-2)
+
+	var s strings.Builder
+	s.WriteString(ui.MutedText.Render("       M T W T F S S") + "\n")
+	for week := range max(weeks, 0) {
+		start := monday.AddDate(0, 0, -7*week)
+		s.WriteString(ui.MutedText.Render(start.Format("Jan 02")))
+		for day := range 7 {
+			date := start.AddDate(0, 0, day)
+			if date.After(today) {
+				break
+			}
+			s.WriteString(" " + heatmapSquare(byDate[date.Format("2006-01-02")]))
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString("\n" + ui.MutedText.Render(fmt.Sprintf("less %s more", strings.Join(heatmapLevels, ""))))
+
+	return ui.TitledPanel("Activity", s.String(), width, height)
+}
+
+// RenderSummary renders the stats panel's main figures on one line, for
+// when there's no room for the panel
+func (m Model) RenderSummary() string {
+	if m.err != nil || m.overview == nil {
+		return ""
+	}
+	today := "no habits due today"
+	if len(m.dailyStats) > 0 && m.dailyStats[0].Total > 0 {
+		today = fmt.Sprintf("%d/%d today", m.dailyStats[0].Completed, m.dailyStats[0].Total)
+	}
+	return ui.MutedText.Render(fmt.Sprintf("%s · best streak %d days · %.0f%% overall",
+		today, m.overview.CurrentBestStreak, m.overview.OverallRate))
+}
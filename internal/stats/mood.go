@@ -0,0 +1,251 @@
+package stats
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+// MoodWindows are the spans of days the mood view can look back over
+var MoodWindows = []int{30, 90}
+
+// minMoodSamples is the fewest check-ins worth correlating
+const minMoodSamples = 5
+
+// MoodStats relates daily check-ins to habit completion over a window of days
+type MoodStats struct {
+	Days      int
+	CheckIns  int
+	AvgMood   float64
+	AvgEnergy float64
+
+	// Pearson correlation with the daily completion rate, NaN when there
+	// are too few check-ins or nothing varies
+	MoodRate   float64
+	EnergyRate float64
+
+	Habits []HabitMood
+}
+
+// HabitMood compares the average mood on days a habit was done with days it wasn't
+type HabitMood struct {
+	HabitID    int64
+	Name       string
+	Emoji      string
+	DaysDone   int
+	DaysMissed int
+	MoodDone   float64
+	MoodMissed float64
+}
+
+// Difference returns how much better mood was on days the habit was done
+func (h HabitMood) Difference() float64 {
+	return h.MoodDone - h.MoodMissed
+}
+
+// Comparable reports whether there are check-ins both with and without the habit
+func (h HabitMood) Comparable() bool {
+	return h.DaysDone > 0 && h.DaysMissed > 0
+}
+
+// HabitDays holds the days in a window an active habit existed and was completed
+type HabitDays struct {
+	ID        int64
+	Name      string
+	Emoji     string
+	Created   time.Time
+	Completed map[string]bool
+}
+
+// GetHabitDays returns every active habit with the dates it was completed on
+// or after start
+func (r *Repository) GetHabitDays(ctx context.Context, start time.Time) ([]HabitDays, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, emoji, date(created_at) FROM habits
+		WHERE archived_at IS NULL
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var habits []HabitDays
+	index := make(map[int64]int)
+	for rows.Next() {
+		var h HabitDays
+		var created string
+		if err := rows.Scan(&h.ID, &h.Name, &h.Emoji, &created); err != nil {
+			return nil, err
+		}
+		h.Created, _ = time.Parse("2006-01-02", created)
+		h.Completed = make(map[string]bool)
+		index[h.ID] = len(habits)
+		habits = append(habits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	completions, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT habit_id, substr(completed_at, 1, 10) FROM completions
+		WHERE completed_at >= ?
+	`, start.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer completions.Close()
+
+	for completions.Next() {
+		var id int64
+		var date string
+		if err := completions.Scan(&id, &date); err != nil {
+			return nil, err
+		}
+		if i, ok := index[id]; ok {
+			habits[i].Completed[date] = true
+		}
+	}
+	return habits, completions.Err()
+}
+
+// GetMoodStats correlates check-ins with habit completion over the last days
+func (s *Service) GetMoodStats(ctx context.Context, days int) (*MoodStats, error) {
+	now := s.clock.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day()-days+1, 0, 0, 0, 0, now.Location())
+
+	checkIns, err := s.checkIns.ListSince(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := s.repo.GetDailyStats(ctx, now, days)
+	if err != nil {
+		return nil, err
+	}
+	habits, err := s.repo.GetHabitDays(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	return moodStats(days, now.Format("2006-01-02"), checkIns, daily, habits), nil
+}
+
+// moodStats does the arithmetic for GetMoodStats. Today is still in
+// progress, so its completion rate is left out and a habit not done yet
+// today doesn't count as missed.
+func moodStats(days int, today string, checkIns []model.CheckIn, daily []DailyStats, habits []HabitDays) *MoodStats {
+	stats := &MoodStats{Days: days, CheckIns: len(checkIns), MoodRate: math.NaN(), EnergyRate: math.NaN()}
+	if len(checkIns) == 0 {
+		return stats
+	}
+
+	rates := make(map[string]float64)
+	for _, d := range daily {
+		if d.Total > 0 {
+			rates[d.Date.Format("2006-01-02")] = float64(d.Completed) / float64(d.Total)
+		}
+	}
+
+	var moods, energies, rateMoods, rateEnergies, rateValues []float64
+	for _, c := range checkIns {
+		moods = append(moods, float64(c.Mood))
+		energies = append(energies, float64(c.Energy))
+		if rate, ok := rates[c.Date.Format("2006-01-02")]; ok && c.Date.Format("2006-01-02") != today {
+			rateMoods = append(rateMoods, float64(c.Mood))
+			rateEnergies = append(rateEnergies, float64(c.Energy))
+			rateValues = append(rateValues, rate)
+		}
+	}
+	stats.AvgMood = mean(moods)
+	stats.AvgEnergy = mean(energies)
+	stats.MoodRate = correlation(rateMoods, rateValues)
+	stats.EnergyRate = correlation(rateEnergies, rateValues)
+
+	for _, h := range habits {
+		hm := HabitMood{HabitID: h.ID, Name: h.Name, Emoji: h.Emoji}
+		var done, missed float64
+		for _, c := range checkIns {
+			date := c.Date.Format("2006-01-02")
+			if c.Date.Before(h.Created) || (date == today && !h.Completed[date]) {
+				continue
+			}
+			if h.Completed[date] {
+				hm.DaysDone++
+				done += float64(c.Mood)
+			} else {
+				hm.DaysMissed++
+				missed += float64(c.Mood)
+			}
+		}
+		if hm.DaysDone > 0 {
+			hm.MoodDone = done / float64(hm.DaysDone)
+		}
+		if hm.DaysMissed > 0 {
+			hm.MoodMissed = missed / float64(hm.DaysMissed)
+		}
+		stats.Habits = append(stats.Habits, hm)
+	}
+
+	// Habits with the biggest lift first; ones without a comparison last
+	sort.SliceStable(stats.Habits, func(i, j int) bool {
+		a, b := stats.Habits[i], stats.Habits[j]
+		if a.Comparable() != b.Comparable() {
+			return a.Comparable()
+		}
+		return a.Difference() > b.Difference()
+	})
+	return stats
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// correlation returns Pearson's r for paired samples, or NaN when there are
+// fewer than minMoodSamples or either side doesn't vary
+func correlation(xs, ys []float64) float64 {
+	if len(xs) != len(ys) || len(xs) < minMoodSamples {
+		return math.NaN()
+	}
+	mx, my := mean(xs), mean(ys)
+	var cov, vx, vy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(vx*vy)
+}
+
+// describeCorrelation puts r into words, e.g. "moderate positive"
+func describeCorrelation(r float64) string {
+	if math.IsNaN(r) {
+		return "not enough data"
+	}
+	strength := "strong"
+	switch a := math.Abs(r); {
+	case a < 0.1:
+		return "no link"
+	case a < 0.3:
+		strength = "weak"
+	case a < 0.5:
+		strength = "moderate"
+	}
+	if r < 0 {
+		return strength + " negative"
+	}
+	return strength + " positive"
+}
@@ -1,6 +1,8 @@
 package stats
 
 import (
+	"context"
+	"database/sql"
 	"time"
 
 	"github.com/vittolewerissa/hbt/internal/shared/db"
@@ -16,22 +18,29 @@ func NewRepository(database *db.DB) *Repository {
 	return &Repository{db: database}
 }
 
+// localTime formats t as a local date and time for SQLite's date
+// functions, in place of 'now'
+func localTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
 // DailyStats represents completion stats for a single day
 type DailyStats struct {
 	Date      time.Time
 	Completed int
 	Total     int
+	Color     string // color of the category with the most completions, if any
 }
 
-// GetDailyStats returns completion stats for the last N days
-func (r *Repository) GetDailyStats(days int) ([]DailyStats, error) {
+// GetDailyStats returns completion stats for the N days up to now
+func (r *Repository) GetDailyStats(ctx context.Context, now time.Time, days int) ([]DailyStats, error) {
 	query := `
 		WITH RECURSIVE dates(date) AS (
-			SELECT date('now', 'localtime')
+			SELECT date(?)
 			UNION ALL
 			SELECT date(date, '-1 day')
 			FROM dates
-			WHERE date > date('now', 'localtime', ? || ' days')
+			WHERE date > date(?, ? || ' days')
 		),
 		daily_habits AS (
 			SELECT d.date, h.id as habit_id
@@ -44,14 +53,23 @@ func (r *Repository) GetDailyStats(days int) ([]DailyStats, error) {
 		SELECT
 			dh.date,
 			COUNT(DISTINCT c.habit_id) as completed,
-			COUNT(DISTINCT dh.habit_id) as total
+			COUNT(DISTINCT dh.habit_id) as total,
+			(SELECT cat.color FROM completions c2
+			 JOIN habits h2 ON c2.habit_id = h2.id
+			 JOIN categories cat ON h2.category_id = cat.id
+			 WHERE c2.completed_at = dh.date
+			 AND h2.archived_at IS NULL
+			 AND h2.frequency_type = 'daily'
+			 GROUP BY cat.id
+			 ORDER BY COUNT(DISTINCT h2.id) DESC, cat.position
+			 LIMIT 1) as color
 		FROM daily_habits dh
 		LEFT JOIN completions c ON dh.habit_id = c.habit_id AND dh.date = c.completed_at
 		GROUP BY dh.date
 		ORDER BY dh.date DESC
 	`
 
-	rows, err := r.db.Query(query, -days+1)
+	rows, err := r.db.QueryContext(ctx, query, localTime(now), localTime(now), -days+1)
 	if err != nil {
 		return nil, err
 	}
@@ -61,25 +79,27 @@ func (r *Repository) GetDailyStats(days int) ([]DailyStats, error) {
 	for rows.Next() {
 		var s DailyStats
 		var dateStr string
-		if err := rows.Scan(&dateStr, &s.Completed, &s.Total); err != nil {
+		var color sql.NullString
+		if err := rows.Scan(&dateStr, &s.Completed, &s.Total, &color); err != nil {
 			return nil, err
 		}
 		s.Date, _ = time.Parse("2006-01-02", dateStr)
+		s.Color = color.String
 		stats = append(stats, s)
 	}
 
 	return stats, rows.Err()
 }
 
-// GetWeeklyStats returns completion stats for the last N weeks
-func (r *Repository) GetWeeklyStats(weeks int) ([]DailyStats, error) {
+// GetWeeklyStats returns completion stats for the N weeks up to now
+func (r *Repository) GetWeeklyStats(ctx context.Context, now time.Time, weeks int) ([]DailyStats, error) {
 	query := `
 		WITH RECURSIVE week_starts(week_start) AS (
-			SELECT date('now', 'localtime', 'weekday 0', '-6 days')
+			SELECT date(?, 'weekday 0', '-6 days')
 			UNION ALL
 			SELECT date(week_start, '-7 days')
 			FROM week_starts
-			WHERE week_start > date('now', 'localtime', ? || ' days')
+			WHERE week_start > date(?, ? || ' days')
 		)
 		SELECT
 			ws.week_start as date,
@@ -96,7 +116,7 @@ func (r *Repository) GetWeeklyStats(weeks int) ([]DailyStats, error) {
 		ORDER BY ws.week_start DESC
 	`
 
-	rows, err := r.db.Query(query, -weeks*7)
+	rows, err := r.db.QueryContext(ctx, query, localTime(now), localTime(now), -(weeks-1)*7)
 	if err != nil {
 		return nil, err
 	}
@@ -118,32 +138,34 @@ func (r *Repository) GetWeeklyStats(weeks int) ([]DailyStats, error) {
 
 // HabitStats represents statistics for a single habit
 type HabitStats struct {
-	HabitID       int64
-	HabitName     string
-	CurrentStreak int
-	BestStreak    int
-	TotalDays     int
-	CompletedDays int
+	HabitID        int64
+	HabitName      string
+	CurrentStreak  int
+	BestStreak     int
+	TotalDays      int
+	CompletedDays  int
 	CompletionRate float64
+	CategoryColor  string
 }
 
-// GetHabitStats returns detailed stats for all habits
-func (r *Repository) GetHabitStats() ([]HabitStats, error) {
+// GetHabitStats returns detailed stats for all habits as of now, with the
+// days done read from the habit_stats cache rather than each history
+func (r *Repository) GetHabitStats(ctx context.Context, now time.Time) ([]HabitStats, error) {
 	query := `
 		SELECT
 			h.id,
 			h.name,
-			COALESCE(
-				(SELECT COUNT(*) FROM completions c WHERE c.habit_id = h.id),
-				0
-			) as completed_days,
-			CAST(julianday('now', 'localtime') - julianday(h.created_at) + 1 AS INTEGER) as total_days
+			COALESCE(cat.color, ''),
+			COALESCE(hs.days_completed, 0) as completed_days,
+			CAST(julianday(?) - julianday(h.created_at) + 1 AS INTEGER) as total_days
 		FROM habits h
+		LEFT JOIN habit_stats hs ON hs.habit_id = h.id
+		LEFT JOIN categories cat ON h.category_id = cat.id
 		WHERE h.archived_at IS NULL
 		ORDER BY h.name
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query, localTime(now))
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +174,7 @@ func (r *Repository) GetHabitStats() ([]HabitStats, error) {
 	var stats []HabitStats
 	for rows.Next() {
 		var s HabitStats
-		if err := rows.Scan(&s.HabitID, &s.HabitName, &s.CompletedDays, &s.TotalDays); err != nil {
+		if err := rows.Scan(&s.HabitID, &s.HabitName, &s.CategoryColor, &s.CompletedDays, &s.TotalDays); err != nil {
 			return nil, err
 		}
 		if s.TotalDays > 0 {
@@ -164,19 +186,19 @@ func (r *Repository) GetHabitStats() ([]HabitStats, error) {
 	return stats, rows.Err()
 }
 
-// GetOverallStats returns overall completion statistics
-func (r *Repository) GetOverallStats() (completed, total int, err error) {
+// GetOverallStats returns overall completion statistics as of now
+func (r *Repository) GetOverallStats(ctx context.Context, now time.Time) (completed, total int, err error) {
 	query := `
 		SELECT
 			COALESCE((SELECT COUNT(*) FROM completions c
 			 JOIN habits h ON c.habit_id = h.id
 			 WHERE h.archived_at IS NULL), 0) as completed,
 			COALESCE((SELECT SUM(
-				CAST(julianday('now', 'localtime') - julianday(h.created_at) + 1 AS INTEGER)
+				CAST(julianday(?) - julianday(h.created_at) + 1 AS INTEGER)
 			 ) FROM habits h
 			 WHERE h.archived_at IS NULL
 			 AND h.frequency_type = 'daily'), 0) as total
 	`
-	err = r.db.QueryRow(query).Scan(&completed, &total)
+	err = r.db.QueryRowContext(ctx, query, localTime(now)).Scan(&completed, &total)
 	return
 }
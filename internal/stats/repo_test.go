@@ -0,0 +1,185 @@
+package stats
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/db/dbtest"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+func TestMain(m *testing.M) {
+	// Creation times are stored in UTC and compared with local dates, so
+	// pin the zone to keep expectations the same on every machine
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+// at returns a time on a day in the local zone
+func at(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.Local)
+}
+
+// dayStats is a DailyStats row in a form that's easy to compare
+type dayStats struct {
+	Date             string
+	Completed, Total int
+}
+
+func asDayStats(stats []DailyStats) []dayStats {
+	var out []dayStats
+	for _, s := range stats {
+		out = append(out, dayStats{s.Date.Format("2006-01-02"), s.Completed, s.Total})
+	}
+	return out
+}
+
+func TestGetDailyStats(t *testing.T) {
+	database := dbtest.Open(t, at(2026, 1, 2, 10))
+	archivedAt := at(2026, 1, 2, 8)
+
+	daily := dbtest.AddHabit(t, database, model.Habit{Name: "Daily", CreatedAt: at(2025, 12, 29, 9)})
+	dbtest.Complete(t, database, daily, "2025-12-30", "2025-12-31", "2025-12-31", "2026-01-02")
+	// Only counted from the day it was created
+	newer := dbtest.AddHabit(t, database, model.Habit{Name: "Newer", CreatedAt: at(2025, 12, 31, 12)})
+	dbtest.Complete(t, database, newer, "2026-01-01")
+	// Weekly and archived habits are left out
+	weekly := dbtest.AddHabit(t, database, model.Habit{Name: "Weekly", FrequencyType: model.FreqWeekly, CreatedAt: at(2025, 12, 1, 9)})
+	dbtest.Complete(t, database, weekly, "2026-01-01")
+	archived := dbtest.AddHabit(t, database, model.Habit{Name: "Archived", CreatedAt: at(2025, 12, 1, 9), ArchivedAt: &archivedAt})
+	dbtest.Complete(t, database, archived, "2026-01-01")
+
+	stats, err := NewRepository(database).GetDailyStats(t.Context(), database.Clock.Now(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []dayStats{
+		{"2026-01-02", 1, 2},
+		{"2026-01-01", 1, 2},
+		{"2025-12-31", 1, 2}, // two completions, one habit
+		{"2025-12-30", 1, 1},
+	}
+	if got := asDayStats(stats); !slices.Equal(got, want) {
+		t.Errorf("GetDailyStats() = %v, want %v", got, want)
+	}
+}
+
+func TestGetDailyStatsColor(t *testing.T) {
+	database := dbtest.Open(t, at(2026, 1, 3, 10))
+	created := at(2025, 12, 1, 9)
+	health := dbtest.Exec(t, database, `INSERT INTO categories (name, color, position, created_at) VALUES ('Health', '#FF6B6B', 1, ?)`, db.Timestamp(created))
+	learning := dbtest.Exec(t, database, `INSERT INTO categories (name, color, position, created_at) VALUES ('Learning', '#45B7D1', 0, ?)`, db.Timestamp(created))
+
+	run := dbtest.AddHabit(t, database, model.Habit{Name: "Run", CategoryID: &health, CreatedAt: created})
+	stretch := dbtest.AddHabit(t, database, model.Habit{Name: "Stretch", CategoryID: &health, CreatedAt: created})
+	read := dbtest.AddHabit(t, database, model.Habit{Name: "Read", CategoryID: &learning, CreatedAt: created})
+	other := dbtest.AddHabit(t, database, model.Habit{Name: "Other", CreatedAt: created})
+	dbtest.Complete(t, database, run, "2026-01-03", "2026-01-02")
+	dbtest.Complete(t, database, stretch, "2026-01-03")
+	dbtest.Complete(t, database, read, "2026-01-03", "2026-01-02", "2026-01-02")
+	dbtest.Complete(t, database, other, "2026-01-01")
+
+	stats, err := NewRepository(database).GetDailyStats(t.Context(), database.Clock.Now(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"#FF6B6B", // most habits done were Health
+		"#45B7D1", // a tie goes to the category first in the manual order
+		"",        // only uncategorized habits done
+	}
+	for i, w := range want {
+		if stats[i].Color != w {
+			t.Errorf("%s color = %q, want %q", stats[i].Date.Format("2006-01-02"), stats[i].Color, w)
+		}
+	}
+}
+
+func TestGetWeeklyStats(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want []dayStats
+	}{
+		{
+			name: "on a Sunday",
+			now:  at(2026, 1, 4, 20),
+			want: []dayStats{{"2025-12-29", 2, 7}, {"2025-12-22", 1, 7}},
+		},
+		{
+			name: "on a Monday",
+			now:  at(2026, 1, 5, 7),
+			want: []dayStats{{"2026-01-05", 1, 7}, {"2025-12-29", 2, 7}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := dbtest.Open(t, tt.now)
+			id := dbtest.AddHabit(t, database, model.Habit{Name: "Daily", CreatedAt: at(2025, 12, 20, 9)})
+			dbtest.Complete(t, database, id, "2025-12-28", "2025-12-29", "2026-01-04", "2026-01-05")
+
+			stats, err := NewRepository(database).GetWeeklyStats(t.Context(), tt.now, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := asDayStats(stats); !slices.Equal(got, tt.want) {
+				t.Errorf("GetWeeklyStats() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetHabitStats(t *testing.T) {
+	now := at(2026, 1, 10, 18)
+	database := dbtest.Open(t, now)
+	archivedAt := at(2026, 1, 9, 8)
+
+	read := dbtest.AddHabit(t, database, model.Habit{Name: "Read", CreatedAt: at(2026, 1, 1, 9)})
+	dbtest.Complete(t, database, read, "2026-01-01", "2026-01-02", "2026-01-02", "2026-01-10")
+	kitchen := dbtest.Exec(t, database, `INSERT INTO categories (name, color, position, created_at) VALUES ('Kitchen', '#FFEAA7', 0, ?)`, db.Timestamp(now))
+	dbtest.AddHabit(t, database, model.Habit{Name: "Cook", CategoryID: &kitchen, CreatedAt: at(2026, 1, 10, 9)})
+	archived := dbtest.AddHabit(t, database, model.Habit{Name: "Archived", CreatedAt: at(2026, 1, 1, 9), ArchivedAt: &archivedAt})
+	dbtest.Complete(t, database, archived, "2026-01-01")
+
+	stats, err := NewService(database).GetHabitStats(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HabitStats{
+		{HabitName: "Cook", TotalDays: 1, CategoryColor: "#FFEAA7"},
+		// A day done twice counts once
+		{HabitName: "Read", CurrentStreak: 1, BestStreak: 2, TotalDays: 10, CompletedDays: 3, CompletionRate: 30},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("got %d habits, want %d", len(stats), len(want))
+	}
+	for i, w := range want {
+		w.HabitID = stats[i].HabitID
+		if stats[i] != w {
+			t.Errorf("habit %d = %+v, want %+v", i, stats[i], w)
+		}
+	}
+}
+
+func TestGetOverallStats(t *testing.T) {
+	now := at(2026, 1, 10, 18)
+	database := dbtest.Open(t, now)
+
+	daily := dbtest.AddHabit(t, database, model.Habit{Name: "Daily", CreatedAt: at(2026, 1, 1, 9)})
+	dbtest.Complete(t, database, daily, "2026-01-01", "2026-01-02", "2026-01-02")
+	weekly := dbtest.AddHabit(t, database, model.Habit{Name: "Weekly", FrequencyType: model.FreqWeekly, CreatedAt: at(2026, 1, 1, 9)})
+	dbtest.Complete(t, database, weekly, "2026-01-05")
+
+	completed, total, err := NewRepository(database).GetOverallStats(t.Context(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every completion counts, but only daily habits make up the total
+	if completed != 4 || total != 10 {
+		t.Errorf("GetOverallStats() = %d, %d, want 4, 10", completed, total)
+	}
+}
@@ -1,6 +1,11 @@
 package stats
 
 import (
+	"context"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/checkin"
+	"github.com/vittolewerissa/hbt/internal/shared/clock"
 	"github.com/vittolewerissa/hbt/internal/shared/db"
 	"github.com/vittolewerissa/hbt/internal/today"
 )
@@ -9,6 +14,8 @@ import (
 type Service struct {
 	repo      *Repository
 	todayRepo *today.Repository
+	checkIns  *checkin.Service
+	clock     clock.Clock
 }
 
 // NewService creates a new stats service
@@ -16,6 +23,8 @@ func NewService(database *db.DB) *Service {
 	return &Service{
 		repo:      NewRepository(database),
 		todayRepo: today.NewRepository(database),
+		checkIns:  checkin.NewService(database),
+		clock:     database.Clock,
 	}
 }
 
@@ -29,14 +38,10 @@ type Overview struct {
 	AllTimeBestStreak int
 }
 
-// GetOverview returns overall statistics
-func (s *Service) GetOverview() (*Overview, error) {
-	completed, total, err := s.repo.GetOverallStats()
-	if err != nil {
-		return nil, err
-	}
-
-	habitStats, err := s.repo.GetHabitStats()
+// GetOverview returns overall statistics, taking the streaks from the
+// per-habit stats so histories aren't read twice
+func (s *Service) GetOverview(ctx context.Context, habitStats []HabitStats) (*Overview, error) {
+	completed, total, err := s.repo.GetOverallStats(ctx, s.clock.Now())
 	if err != nil {
 		return nil, err
 	}
@@ -53,14 +58,11 @@ func (s *Service) GetOverview() (*Overview, error) {
 
 	// Find best streaks
 	for _, h := range habitStats {
-		streak, _ := s.todayRepo.CalculateCurrentStreak(h.HabitID)
-		if streak > overview.CurrentBestStreak {
-			overview.CurrentBestStreak = streak
+		if h.CurrentStreak > overview.CurrentBestStreak {
+			overview.CurrentBestStreak = h.CurrentStreak
 		}
-
-		best, _ := s.todayRepo.CalculateBestStreak(h.HabitID)
-		if best > overview.AllTimeBestStreak {
-			overview.AllTimeBestStreak = best
+		if h.BestStreak > overview.AllTimeBestStreak {
+			overview.AllTimeBestStreak = h.BestStreak
 		}
 	}
 
@@ -68,26 +70,44 @@ func (s *Service) GetOverview() (*Overview, error) {
 }
 
 // GetDailyStats returns daily completion stats
-func (s *Service) GetDailyStats(days int) ([]DailyStats, error) {
-	return s.repo.GetDailyStats(days)
+func (s *Service) GetDailyStats(ctx context.Context, days int) ([]DailyStats, error) {
+	return s.repo.GetDailyStats(ctx, s.clock.Now(), days)
+}
+
+// GetHeatmapStats returns daily completion stats back to the Monday the
+// heatmap starts on
+func (s *Service) GetHeatmapStats(ctx context.Context) ([]DailyStats, error) {
+	now := s.clock.Now()
+	return s.repo.GetDailyStats(ctx, now, (heatmapWeeks-1)*7+daysSinceMonday(now)+1)
+}
+
+// daysSinceMonday returns how many days into its week t is
+func daysSinceMonday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
 }
 
 // GetWeeklyStats returns weekly completion stats
-func (s *Service) GetWeeklyStats(weeks int) ([]DailyStats, error) {
-	return s.repo.GetWeeklyStats(weeks)
+func (s *Service) GetWeeklyStats(ctx context.Context, weeks int) ([]DailyStats, error) {
+	return s.repo.GetWeeklyStats(ctx, s.clock.Now(), weeks)
 }
 
 // GetHabitStats returns per-habit statistics
-func (s *Service) GetHabitStats() ([]HabitStats, error) {
-	stats, err := s.repo.GetHabitStats()
+func (s *Service) GetHabitStats(ctx context.Context) ([]HabitStats, error) {
+	now := s.clock.Now()
+	stats, err := s.repo.GetHabitStats(ctx, now)
 	if err != nil {
 		return nil, err
 	}
 
-	// Add streak info
+	// Add streak info from the cache kept with completions
+	cached, err := s.todayRepo.CachedStats(ctx, false)
+	if err != nil {
+		return nil, err
+	}
 	for i := range stats {
-		stats[i].CurrentStreak, _ = s.todayRepo.CalculateCurrentStreak(stats[i].HabitID)
-		stats[i].BestStreak, _ = s.todayRepo.CalculateBestStreak(stats[i].HabitID)
+		c := cached[stats[i].HabitID]
+		stats[i].CurrentStreak = c.CurrentStreak(now)
+		stats[i].BestStreak = c.BestStreak
 	}
 
 	return stats, nil
@@ -2,41 +2,52 @@ package stats
 
 import (
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/emoji"
 	"github.com/vittolewerissa/hbt/internal/shared/ui"
 )
 
-// View modes
+// Panel views, cycled with the stats view key
 type viewMode int
 
 const (
 	modeOverview viewMode = iota
 	modeHabits
+	modeMood
 )
 
-// Model is the stats tab model
+// Model is the stats panel model
 type Model struct {
-	service     *Service
-	overview    *Overview
-	habitStats  []HabitStats
-	dailyStats  []DailyStats
-	mode        viewMode
-	cursor      int
-	width       int
-	height      int
-	keys        ui.KeyMap
-	err         error
+	service    *Service
+	loads      *ui.Loader
+	overview   *Overview
+	habitStats []HabitStats
+	dailyStats []DailyStats
+	history    []DailyStats // back to the start of the heatmap
+	mood       []*MoodStats // one per MoodWindows entry
+	moodWindow int          // index into MoodWindows
+	mode       viewMode
+	list       ui.ScrollList // per-habit view
+	moodList   ui.ScrollList // habits in the mood view
+	focused    bool
+	height     int
+	keys       ui.KeyMap
+	err        error
 }
 
 // New creates a new stats model
-func New(database *db.DB) Model {
+func New(database *db.DB, keys ui.KeyMap) Model {
 	return Model{
 		service: NewService(database),
-		keys:    ui.DefaultKeyMap,
+		loads:   &ui.Loader{},
+		keys:    keys,
 	}
 }
 
@@ -50,29 +61,45 @@ type StatsLoadedMsg struct {
 	Overview   *Overview
 	HabitStats []HabitStats
 	DailyStats []DailyStats
+	History    []DailyStats
+	Mood       []*MoodStats
 	Err        error
 }
 
 func (m Model) loadData() tea.Msg {
-	overview, err := m.service.GetOverview()
+	ctx, cancel := m.loads.Start()
+	defer cancel()
+	habitStats, err := m.service.GetHabitStats(ctx)
 	if err != nil {
 		return StatsLoadedMsg{Err: err}
 	}
 
-	habitStats, err := m.service.GetHabitStats()
+	overview, err := m.service.GetOverview(ctx, habitStats)
 	if err != nil {
 		return StatsLoadedMsg{Err: err}
 	}
 
-	dailyStats, err := m.service.GetDailyStats(14) // Last 2 weeks
+	history, err := m.service.GetHeatmapStats(ctx)
 	if err != nil {
 		return StatsLoadedMsg{Err: err}
 	}
+	dailyStats := history[:min(len(history), 14)] // Last 2 weeks
+
+	var mood []*MoodStats
+	for _, days := range MoodWindows {
+		stats, err := m.service.GetMoodStats(ctx, days)
+		if err != nil {
+			return StatsLoadedMsg{Err: err}
+		}
+		mood = append(mood, stats)
+	}
 
 	return StatsLoadedMsg{
 		Overview:   overview,
 		HabitStats: habitStats,
 		DailyStats: dailyStats,
+		History:    history,
+		Mood:       mood,
 	}
 }
 
@@ -80,191 +107,147 @@ func (m Model) loadData() tea.Msg {
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case StatsLoadedMsg:
+		if ui.Superseded(msg.Err) {
+			return m, nil
+		}
 		if msg.Err != nil {
 			m.err = msg.Err
 			return m, nil
 		}
+		m.err = nil
 		m.overview = msg.Overview
 		m.habitStats = msg.HabitStats
 		m.dailyStats = msg.DailyStats
-		return m, nil
-
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-
-	case tea.KeyMsg:
-		return m.handleKey(msg)
-	}
-
-	return m, nil
-}
-
-func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
-	switch {
-	case key.Matches(msg, m.keys.Left):
-		if m.mode > modeOverview {
-			m.mode--
-		}
-	case key.Matches(msg, m.keys.Right):
-		if m.mode < modeHabits {
-			m.mode++
-		}
-	case key.Matches(msg, m.keys.Up):
-		if m.mode == modeHabits && m.cursor > 0 {
-			m.cursor--
-		}
-	case key.Matches(msg, m.keys.Down):
-		if m.mode == modeHabits && m.cursor < len(m.habitStats)-1 {
-			m.cursor++
+		m.history = msg.History
+		m.mood = msg.Mood
+		m.list.Clamp(len(m.habitStats))
+		m.moodList.Clamp(len(m.moodHabits()))
+		m.syncScroll()
+
+	case ui.ScrollMsg:
+		switch m.mode {
+		case modeHabits:
+			m.list.Move(msg.Delta, len(m.habitStats))
+		case modeMood:
+			m.moodList.Move(msg.Delta, len(m.moodHabits()))
 		}
+		m.syncScroll()
 	}
-
 	return m, nil
 }
 
-// View renders the stats tab
-func (m Model) View() string {
-	if m.err != nil {
-		return ui.MutedText.Render(fmt.Sprintf("Error: %v", m.err))
-	}
-
-	var s string
-	s += ui.Title.Render("Statistics") + "\n\n"
-
-	// Tab selector
-	tabs := []string{"Overview", "Per Habit"}
-	var tabLine string
-	for i, tab := range tabs {
-		if viewMode(i) == m.mode {
-			tabLine += ui.SelectedItem.Render("["+tab+"]") + "  "
-		} else {
-			tabLine += ui.MutedText.Render(" "+tab+" ") + "  "
-		}
-	}
-	s += tabLine + "\n\n"
-
+// HandleKey moves through the list of the current view while the panel
+// has focus, and reports whether the key was used
+func (m *Model) HandleKey(msg tea.KeyMsg) bool {
+	var used bool
 	switch m.mode {
-	case modeOverview:
-		s += m.renderOverview()
 	case modeHabits:
-		s += m.renderHabitStats()
+		used = m.list.HandleKey(msg, m.keys, len(m.habitStats))
+	case modeMood:
+		used = m.moodList.HandleKey(msg, m.keys, len(m.moodHabits()))
 	}
-
-	s += "\n" + ui.MutedText.Render("←/→: switch view  ↑/↓: navigate")
-
-	return s
+	m.syncScroll()
+	return used
 }
 
-func (m Model) renderOverview() string {
-	if m.overview == nil {
-		return ui.MutedText.Render("Loading...")
-	}
-
-	var s string
-
-	// Summary stats
-	s += lipgloss.NewStyle().Bold(true).Render("Summary") + "\n"
-	s += fmt.Sprintf("  Total habits: %d\n", m.overview.TotalHabits)
-	s += fmt.Sprintf("  Total completions: %d\n", m.overview.TotalCompletions)
-	s += fmt.Sprintf("  Overall rate: %.1f%%\n", m.overview.OverallRate)
-	s += fmt.Sprintf("  Current best streak: %d days\n", m.overview.CurrentBestStreak)
-	s += fmt.Sprintf("  All-time best streak: %d days\n", m.overview.AllTimeBestStreak)
-	s += "\n"
-
-	// Daily sparkline
-	if len(m.dailyStats) > 0 {
-		s += lipgloss.NewStyle().Bold(true).Render("Last 14 Days") + "\n"
-
-		// Convert to percentages for sparkline
-		var values []float64
-		for i := len(m.dailyStats) - 1; i >= 0; i-- {
-			stat := m.dailyStats[i]
-			if stat.Total > 0 {
-				values = append(values, float64(stat.Completed)/float64(stat.Total)*100)
-			} else {
-				values = append(values, 0)
-			}
-		}
-
-		spark := NewSparkline(40)
-		s += "  " + spark.Render(values) + "\n"
-
-		// Show recent days as bar chart
-		s += "\n"
-		chart := NewBarChart(m.width - 10)
-
-		limit := 7
-		if len(m.dailyStats) < limit {
-			limit = len(m.dailyStats)
-		}
-
-		for i := 0; i < limit; i++ {
-			stat := m.dailyStats[i]
-			var pct float64
-			if stat.Total > 0 {
-				pct = float64(stat.Completed) / float64(stat.Total) * 100
-			}
-			label := stat.Date.Format("Mon 01/02")
-			s += "  " + chart.Render(pct, label) + "\n"
-		}
+// NextView switches the panel to its next view: the overview, each habit's
+// completion, then mood over each of the MoodWindows
+func (m *Model) NextView() {
+	m.moodList = ui.ScrollList{}
+	if m.mode == modeMood && m.moodWindow < len(MoodWindows)-1 {
+		m.moodWindow++
+	} else {
+		m.mode = (m.mode + 1) % (modeMood + 1)
+		m.moodWindow = 0
 	}
-
-	return s
+	m.syncScroll()
 }
 
-func (m Model) renderHabitStats() string {
-	if len(m.habitStats) == 0 {
-		return ui.MutedText.Render("No habits yet.")
-	}
+// SetFocused gives the panel the keys for moving through its lists, or
+// hands them back to the tab
+func (m *Model) SetFocused(focused bool) {
+	m.focused = focused
+}
 
-	var s string
-	chart := NewBarChart(m.width - 15)
+// Focused returns whether the panel has the keys for moving through its lists
+func (m Model) Focused() bool {
+	return m.focused
+}
 
-	for i, stat := range m.habitStats {
-		cursor := "  "
-		if i == m.cursor {
-			cursor = "> "
-		}
+// SetHeight sets the height of the panel, so its lists know how many rows
+// they have
+func (m *Model) SetHeight(height int) {
+	m.height = height
+	m.syncScroll()
+}
 
-		// Name line
-		nameStyle := ui.NormalItem
-		if i == m.cursor {
-			nameStyle = ui.SelectedItem
-		}
-		s += cursor + nameStyle.Render(stat.HabitName) + "\n"
+// habitRows is the number of lines each habit takes in the per-habit view
+const habitRows = 2
 
-		// Stats line
-		streakInfo := fmt.Sprintf("    Streak: %d (best: %d)", stat.CurrentStreak, stat.BestStreak)
-		s += ui.MutedText.Render(streakInfo) + "\n"
+// moodHeaderRows is the number of lines above the habits in the mood view
+const moodHeaderRows = 8
 
-		// Completion bar
-		s += "    " + chart.Render(stat.CompletionRate, "") + "\n"
-		s += "\n"
+// syncScroll fits the lists inside the panel's border and padding and keeps
+// the selected habit in view
+func (m *Model) syncScroll() {
+	m.list.Height, m.moodList.Height = 0, 0
+	if rows := m.height - 4; rows > 0 {
+		m.list.Height = rows
+		m.moodList.Height = max(rows-moodHeaderRows, 1)
 	}
-
-	return s
+	first := m.list.Cursor * habitRows
+	m.list.Follow(first, first+habitRows-1, len(m.habitStats)*habitRows)
+	m.moodList.Follow(m.moodList.Cursor, m.moodList.Cursor, len(m.moodHabits()))
 }
 
-// Focused returns whether this view should receive key events
-func (m Model) Focused() bool {
-	return false
+// HelpSections returns the key bindings of the panel while it has focus
+func (m Model) HelpSections() []ui.HelpSection {
+	return []ui.HelpSection{{Title: "Stats", Bindings: []key.Binding{
+		m.keys.Up, m.keys.Down, m.keys.PageUp, m.keys.PageDown, m.keys.Top, m.keys.Bottom,
+		m.keys.StatsView, m.keys.FocusStats,
+	}}}
 }
 
-// RenderPanel renders a compact stats panel for the sidebar
+// RenderPanel renders the stats panel in its current view
 func (m Model) RenderPanel(width, height int) string {
-	var s string
+	title := "Stats"
+	switch m.mode {
+	case modeHabits:
+		title = "Stats · Per Habit"
+	case modeMood:
+		title = fmt.Sprintf("Stats · Mood %dd", MoodWindows[m.moodWindow])
+	}
 
 	if m.err != nil {
-		s += ui.MutedText.Render("Error loading stats")
-		return ui.TitledPanel("Stats", s, width, height)
+		return ui.TitledPanel(title, ui.MutedText.Render("Error loading stats"), width, height)
 	}
-
 	if m.overview == nil {
-		s += ui.MutedText.Render("Loading...")
-		return ui.TitledPanel("Stats", s, width, height)
+		return ui.TitledPanel(title, ui.MutedText.Render("Loading..."), width, height)
 	}
 
+	// Leave room for the panel's border and padding
+	contentWidth := width - 10
+	var content string
+	switch m.mode {
+	case modeOverview:
+		content = m.renderOverview(width)
+	case modeHabits:
+		content = m.renderHabits(contentWidth)
+	case modeMood:
+		content = m.renderMood(contentWidth)
+	}
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, contentWidth, "…")
+	}
+	return ui.TitledPanel(title, strings.Join(lines, "\n"), width, height)
+}
+
+// renderOverview renders today's progress, streaks, the last week and the
+// overall figures
+func (m Model) renderOverview(width int) string {
+	var s string
+
 	// Today's progress
 	s += lipgloss.NewStyle().Bold(true).Render("Today") + "\n"
 	todayCompleted := 0
@@ -315,5 +298,107 @@ func (m Model) RenderPanel(width, height int) string {
 	s += fmt.Sprintf("  %d habits\n", m.overview.TotalHabits)
 	s += fmt.Sprintf("  %.0f%% completion rate\n", m.overview.OverallRate)
 
-	return ui.TitledPanel("Stats", s, width, height)
+	return s
+}
+
+// cursor returns the marker and style for item i of a list whose cursor
+// is at selected, showing the cursor only while the panel has focus
+func (m Model) cursor(i, selected int) (string, lipgloss.Style) {
+	if m.focused && i == selected {
+		return "> ", ui.SelectedItem
+	}
+	return "  ", ui.NormalItem
+}
+
+// renderHabits renders each habit's completion rate as a bar in its
+// category's color
+func (m Model) renderHabits(width int) string {
+	if len(m.habitStats) == 0 {
+		return ui.MutedText.Render("No habits yet")
+	}
+
+	chart := NewBarChart(width - 2)
+	var rows []string
+	for i, stat := range m.habitStats {
+		cursor, nameStyle := m.cursor(i, m.list.Cursor)
+		rows = append(rows, cursor+nameStyle.Render(stat.HabitName)+ui.MutedText.Render(fmt.Sprintf(" streak %d", stat.CurrentStreak)))
+		chart.Color = stat.CategoryColor
+		rows = append(rows, "    "+chart.Render(stat.CompletionRate, ""))
+	}
+	return m.list.Render(rows)
+}
+
+// moodHabits returns the habits listed in the mood view
+func (m Model) moodHabits() []HabitMood {
+	if m.moodWindow < len(m.mood) && m.mood[m.moodWindow].CheckIns > 0 {
+		return m.mood[m.moodWindow].Habits
+	}
+	return nil
+}
+
+// renderMood renders how mood and energy line up with completion over the
+// selected window, and the mood on days each habit was done versus missed
+func (m Model) renderMood(width int) string {
+	if m.moodWindow >= len(m.mood) {
+		return ui.MutedText.Render("Loading...")
+	}
+	mood := m.mood[m.moodWindow]
+
+	bold := lipgloss.NewStyle().Bold(true)
+	s := bold.Render(fmt.Sprintf("Last %d days", mood.Days)) + ui.MutedText.Render(fmt.Sprintf(" · %d check-ins", mood.CheckIns)) + "\n"
+	if mood.CheckIns == 0 {
+		return s + ui.MutedText.Render(fmt.Sprintf("Press '%s' on Today to check in", m.keys.CheckIn.Help().Key))
+	}
+
+	s += fmt.Sprintf("  Mood %.1f · Energy %.1f avg\n", mood.AvgMood, mood.AvgEnergy)
+	s += "\n"
+	s += bold.Render("Completion vs") + "\n"
+	s += "  Mood:   " + formatCorrelation(mood.MoodRate) + "\n"
+	s += "  Energy: " + formatCorrelation(mood.EnergyRate) + "\n"
+	s += "\n"
+	s += bold.Render("Mood done vs not") + "\n"
+
+	const detailWidth = 15 // e.g. "3.5 vs 2.9 +0.6"
+	nameWidth := max(width-4-detailWidth, 6)
+	rows := make([]string, len(mood.Habits))
+	for i, h := range mood.Habits {
+		cursor, nameStyle := m.cursor(i, m.moodList.Cursor)
+		label := ansi.Truncate(habitLabel(h), nameWidth, "…")
+		label = emoji.Pad(label, nameWidth)
+
+		var detail string
+		switch {
+		case h.Comparable():
+			diff := h.Difference()
+			style := ui.MutedText
+			if diff >= 0.5 {
+				style = lipgloss.NewStyle().Foreground(ui.Success)
+			} else if diff <= -0.5 {
+				style = lipgloss.NewStyle().Foreground(ui.Danger)
+			}
+			detail = fmt.Sprintf("%.1f vs %.1f ", h.MoodDone, h.MoodMissed) + style.Render(fmt.Sprintf("%+.1f", diff))
+		case h.DaysDone == 0:
+			detail = ui.MutedText.Render("never done")
+		default:
+			detail = ui.MutedText.Render("always done")
+		}
+		rows[i] = cursor + nameStyle.Render(label) + "  " + detail
+	}
+	return s + m.moodList.Render(rows)
+}
+
+// habitLabel returns a habit's emoji and name
+func habitLabel(h HabitMood) string {
+	if h.Emoji != "" {
+		return h.Emoji + " " + h.Name
+	}
+	return h.Name
+}
+
+// formatCorrelation renders r with its meaning, e.g. "+0.42 moderate positive"
+func formatCorrelation(r float64) string {
+	if math.IsNaN(r) {
+		return ui.MutedText.Render(describeCorrelation(r))
+	}
+	return fmt.Sprintf("%+.2f ", r) + ui.MutedText.Render(describeCorrelation(r))
 }
@@ -0,0 +1,170 @@
+package today
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+)
+
+// CachedStats is a habit's streaks and totals as kept in the habit_stats
+// table, which is updated along with every completion
+type CachedStats struct {
+	Completions    int
+	DaysCompleted  int
+	FirstCompleted string // YYYY-MM-DD, empty if never completed
+	LastCompleted  string
+	LastStreak     int // consecutive days ending on LastCompleted
+	BestStreak     int
+}
+
+// CurrentStreak returns the streak as of now: the run ending on the last
+// completion, if that was today or yesterday
+func (c CachedStats) CurrentStreak(now time.Time) int {
+	if c.LastCompleted == now.Format("2006-01-02") || c.LastCompleted == now.AddDate(0, 0, -1).Format("2006-01-02") {
+		return c.LastStreak
+	}
+	return 0
+}
+
+// CachedStats returns the cached stats of every active habit, or every
+// archived one, keyed by habit ID. Habits never completed are left out.
+func (r *Repository) CachedStats(ctx context.Context, archived bool) (map[int64]CachedStats, error) {
+	condition := "h.archived_at IS NULL"
+	if archived {
+		condition = "h.archived_at IS NOT NULL"
+	}
+	query := `
+		SELECT s.habit_id, s.completions, s.days_completed, s.first_completed, s.last_completed,
+		       s.last_streak, s.best_streak
+		FROM habit_stats s
+		JOIN habits h ON s.habit_id = h.id
+		WHERE ` + condition
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[int64]CachedStats)
+	for rows.Next() {
+		var habitID int64
+		var c CachedStats
+		var first, last sql.NullString
+		if err := rows.Scan(&habitID, &c.Completions, &c.DaysCompleted, &first, &last, &c.LastStreak, &c.BestStreak); err != nil {
+			return nil, err
+		}
+		c.FirstCompleted, c.LastCompleted = first.String, last.String
+		stats[habitID] = c
+	}
+	return stats, rows.Err()
+}
+
+// cachedStats reads one habit's cached stats inside tx
+func cachedStats(ctx context.Context, tx *sql.Tx, habitID int64) (CachedStats, error) {
+	query := `
+		SELECT completions, days_completed, first_completed, last_completed, last_streak, best_streak
+		FROM habit_stats WHERE habit_id = ?
+	`
+	var c CachedStats
+	var first, last sql.NullString
+	err := tx.QueryRowContext(ctx, query, habitID).Scan(&c.Completions, &c.DaysCompleted, &first, &last, &c.LastStreak, &c.BestStreak)
+	if err == sql.ErrNoRows {
+		return c, nil
+	}
+	c.FirstCompleted, c.LastCompleted = first.String, last.String
+	return c, err
+}
+
+// saveCachedStats stores one habit's cached stats inside tx
+func saveCachedStats(ctx context.Context, tx *sql.Tx, habitID int64, c CachedStats) error {
+	query := `
+		INSERT OR REPLACE INTO habit_stats
+			(habit_id, completions, days_completed, first_completed, last_completed, last_streak, best_streak)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := tx.ExecContext(ctx, query, habitID, c.Completions, c.DaysCompleted, c.FirstCompleted, c.LastCompleted, c.LastStreak, c.BestStreak)
+	return err
+}
+
+// countOn returns how many completions a habit has on a date inside tx
+func countOn(ctx context.Context, tx *sql.Tx, habitID int64, date string) (int, error) {
+	var count int
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM completions WHERE habit_id = ? AND completed_at = ?`, habitID, date).Scan(&count)
+	return count, err
+}
+
+// nextDay returns the day after a YYYY-MM-DD date
+func nextDay(date string) string {
+	d, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return ""
+	}
+	return d.AddDate(0, 0, 1).Format("2006-01-02")
+}
+
+// cacheCompleted updates a habit's cached stats for a completion just added
+// on date inside tx. Completing the latest day again or the day after it
+// is worked out in place; anything earlier rebuilds the habit's stats.
+func cacheCompleted(ctx context.Context, tx *sql.Tx, habitID int64, date time.Time) error {
+	day := date.Format("2006-01-02")
+	c, err := cachedStats(ctx, tx, habitID)
+	if err != nil {
+		return err
+	}
+	count, err := countOn(ctx, tx, habitID, day)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case count > 1:
+		// Another completion on a day already counted
+	case c.LastCompleted == "":
+		c.FirstCompleted, c.LastCompleted, c.LastStreak = day, day, 1
+		c.DaysCompleted = 1
+	case day == nextDay(c.LastCompleted):
+		c.LastCompleted = day
+		c.LastStreak++
+		c.DaysCompleted++
+	case day > c.LastCompleted:
+		c.LastCompleted, c.LastStreak = day, 1
+		c.DaysCompleted++
+	default:
+		return db.RebuildHabitStats(ctx, tx, habitID)
+	}
+	c.Completions++
+	c.BestStreak = max(c.BestStreak, c.LastStreak)
+	return saveCachedStats(ctx, tx, habitID, c)
+}
+
+// cacheUncompleted updates a habit's cached stats for a completion just
+// removed from date inside tx. Removing one of several completions on a day,
+// or the last day of a streak that isn't the best, is worked out in place;
+// anything else rebuilds the habit's stats.
+func cacheUncompleted(ctx context.Context, tx *sql.Tx, habitID int64, date time.Time) error {
+	day := date.Format("2006-01-02")
+	c, err := cachedStats(ctx, tx, habitID)
+	if err != nil {
+		return err
+	}
+	count, err := countOn(ctx, tx, habitID, day)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case count > 0:
+		// The day is still completed
+	case day == c.LastCompleted && c.LastStreak > 1 && c.LastStreak < c.BestStreak:
+		d, _ := time.Parse("2006-01-02", day)
+		c.LastCompleted = d.AddDate(0, 0, -1).Format("2006-01-02")
+		c.LastStreak--
+		c.DaysCompleted--
+	default:
+		return db.RebuildHabitStats(ctx, tx, habitID)
+	}
+	c.Completions--
+	return saveCachedStats(ctx, tx, habitID, c)
+}
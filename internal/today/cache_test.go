@@ -0,0 +1,190 @@
+package today
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/db/dbtest"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+// fromScratch works out the stats the cache should hold for every habit
+// from its whole completion history
+func fromScratch(t *testing.T, r *Repository, now time.Time) (map[int64]CachedStats, map[int64]Summary) {
+	t.Helper()
+	days, err := r.CompletionDays(t.Context(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := make(map[int64]CachedStats)
+	summaries := make(map[int64]Summary)
+	for id, d := range days {
+		c := CachedStats{
+			DaysCompleted:  len(d),
+			FirstCompleted: d[0].Date,
+			LastCompleted:  d[len(d)-1].Date,
+			LastStreak:     lastRun(d),
+		}
+		for _, day := range d {
+			c.Completions += day.Count
+		}
+		summaries[id] = Summarize(d, now)
+		c.BestStreak = summaries[id].BestStreak
+		stats[id] = c
+	}
+	return stats, summaries
+}
+
+// lastRun counts the consecutive days ending on the last completed day
+func lastRun(days []DayCount) int {
+	run := 1
+	for i := len(days) - 1; i > 0; i-- {
+		if nextDay(days[i-1].Date) != days[i].Date {
+			break
+		}
+		run++
+	}
+	return run
+}
+
+// assertCacheMatches fails if the cached stats of any habit differ from the
+// from-scratch computation
+func assertCacheMatches(t *testing.T, r *Repository, now time.Time, step string) {
+	t.Helper()
+	got, err := r.CachedStats(t.Context(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, summaries := fromScratch(t, r, now)
+
+	for id := range got {
+		if _, ok := want[id]; !ok {
+			t.Fatalf("%s: habit %d has no completions but cached %+v", step, id, got[id])
+		}
+	}
+	for id, w := range want {
+		if got[id] != w {
+			t.Fatalf("%s: habit %d cached %+v, want %+v", step, id, got[id], w)
+		}
+		if streak := got[id].CurrentStreak(now); streak != summaries[id].CurrentStreak {
+			t.Fatalf("%s: habit %d current streak %d, want %d", step, id, streak, summaries[id].CurrentStreak)
+		}
+	}
+}
+
+// openTestDB opens a new database with the given number of habits
+func openTestDB(t *testing.T, habits int) *db.DB {
+	t.Helper()
+	database := dbtest.Open(t, time.Now())
+	for i := range habits {
+		dbtest.AddHabit(t, database, model.Habit{Name: "Habit", Position: i})
+	}
+	return database
+}
+
+// TestCacheMatchesHistory completes and uncompletes habits on random days,
+// mostly recent ones, checking the cache after every change and after a
+// reindex
+func TestCacheMatchesHistory(t *testing.T) {
+	const habits = 3
+	now := time.Now()
+
+	for seed := range uint64(10) {
+		database := openTestDB(t, habits)
+		r := NewRepository(database)
+		rng := rand.New(rand.NewPCG(seed, seed))
+
+		for step := range 150 {
+			habitID := int64(1 + rng.IntN(habits))
+			// Most changes land on the last few days, like toggling today,
+			// with the odd backfill further back
+			daysAgo := rng.IntN(5)
+			if rng.IntN(5) == 0 {
+				daysAgo = rng.IntN(40)
+			}
+			date := now.AddDate(0, 0, -daysAgo)
+
+			if rng.IntN(3) == 0 {
+				if _, err := r.Uncomplete(t.Context(), habitID, date); err != nil {
+					t.Fatal(err)
+				}
+			} else if err := r.Complete(t.Context(), habitID, date, ""); err != nil {
+				t.Fatal(err)
+			}
+			assertCacheMatches(t, r, now, fmt.Sprintf("seed %d step %d", seed, step))
+		}
+
+		if _, err := database.Reindex(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		assertCacheMatches(t, r, now, fmt.Sprintf("seed %d after reindex", seed))
+	}
+}
+
+// TestReindexMatchesHistory rebuilds the cache from randomly generated
+// histories written straight to the completions table
+func TestReindexMatchesHistory(t *testing.T) {
+	const habits = 5
+	now := time.Now()
+
+	for seed := range uint64(10) {
+		database := openTestDB(t, habits)
+		rng := rand.New(rand.NewPCG(seed, 1))
+
+		for habitID := int64(1); habitID <= habits; habitID++ {
+			// Each habit is done on a random share of days, in streaks
+			rate := 1 + rng.IntN(9)
+			for daysAgo := range 400 {
+				if rng.IntN(10) >= rate {
+					continue
+				}
+				date := now.AddDate(0, 0, -daysAgo).Format("2006-01-02")
+				for range 1 + rng.IntN(2) {
+					if _, err := database.Exec(`INSERT INTO completions (habit_id, completed_at) VALUES (?, ?)`, habitID, date); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+		}
+
+		if _, err := database.Reindex(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		assertCacheMatches(t, NewRepository(database), now, fmt.Sprintf("seed %d", seed))
+	}
+}
+
+func TestCachedCurrentStreak(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := CachedStats{LastCompleted: "2026-03-08", LastStreak: 4, BestStreak: 6}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{"on the last day", time.Date(2026, 3, 8, 23, 0, 0, 0, newYork), 4},
+		// 2026-03-08 was 23 hours long in New York
+		{"just after midnight", time.Date(2026, 3, 9, 0, 5, 0, 0, newYork), 4},
+		{"late the next day", time.Date(2026, 3, 9, 23, 59, 0, 0, newYork), 4},
+		{"two days on", time.Date(2026, 3, 10, 0, 0, 0, 0, newYork), 0},
+		{"before the last day", time.Date(2026, 3, 7, 12, 0, 0, 0, newYork), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.CurrentStreak(tt.now); got != tt.want {
+				t.Errorf("CurrentStreak(%v) = %d, want %d", tt.now, got, tt.want)
+			}
+		})
+	}
+	if got := (CachedStats{}).CurrentStreak(time.Now()); got != 0 {
+		t.Errorf("never completed: CurrentStreak() = %d, want 0", got)
+	}
+}
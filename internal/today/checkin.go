@@ -0,0 +1,212 @@
+package today
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+)
+
+// Check-in form fields
+const (
+	checkInMood = iota
+	checkInEnergy
+	checkInNotes
+	checkInFields
+)
+
+var (
+	moodLabels   = []string{"", "awful", "low", "okay", "good", "great"}
+	energyLabels = []string{"", "drained", "tired", "steady", "lively", "energized"}
+)
+
+// CheckInSavedMsg is sent when today's check-in was saved or skipped
+type CheckInSavedMsg struct {
+	Err error
+}
+
+// checkInForm holds the ratings being entered in the daily check-in
+type checkInForm struct {
+	mood   int
+	energy int
+	notes  textinput.Model
+	field  int
+}
+
+// openCheckIn shows the check-in form, filled in with today's check-in if
+// there is one
+func (m *Model) openCheckIn() tea.Cmd {
+	notes := textinput.New()
+	notes.Placeholder = "Anything on your mind? (optional)"
+	notes.CharLimit = 200
+	notes.Width = max(20, min(50, m.panelWidth-16))
+
+	m.checkIn = checkInForm{mood: 3, energy: 3, notes: notes}
+	if c := m.todayCheckIn; c != nil {
+		m.checkIn.mood, m.checkIn.energy = c.Mood, c.Energy
+		m.checkIn.notes.SetValue(c.Notes)
+	}
+	m.checkInOffered = m.clock.Now().Format("2006-01-02")
+	m.mode = modeCheckIn
+	return nil
+}
+
+// handleCheckInKey handles keys in the check-in form
+func (m Model) handleCheckInKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	f := &m.checkIn
+	onRating := f.field != checkInNotes
+
+	switch {
+	case ui.MatchesNonText(msg, m.keys.Back):
+		m.mode = modeList
+		if m.todayCheckIn != nil {
+			return m, nil
+		}
+		return m, m.skipCheckIn()
+	case ui.MatchesNonText(msg, m.keys.Save), ui.MatchesNonText(msg, m.keys.Select):
+		m.mode = modeList
+		return m, m.saveCheckIn(model.CheckIn{
+			Mood:   f.mood,
+			Energy: f.energy,
+			Notes:  strings.TrimSpace(f.notes.Value()),
+		})
+	case ui.MatchesNonText(msg, m.keys.NextField):
+		return m, m.focusCheckInField((f.field + 1) % checkInFields)
+	case ui.MatchesNonText(msg, m.keys.PrevField):
+		return m, m.focusCheckInField((f.field - 1 + checkInFields) % checkInFields)
+	case onRating && key.Matches(msg, m.keys.Left):
+		f.adjust(-1)
+		return m, nil
+	case onRating && key.Matches(msg, m.keys.Right):
+		f.adjust(1)
+		return m, nil
+	case onRating && msg.Type == tea.KeyRunes && len(msg.Runes) == 1:
+		if r := int(msg.Runes[0] - '0'); r >= model.MinRating && r <= model.MaxRating {
+			f.set(r)
+		}
+		return m, nil
+	}
+
+	if f.field == checkInNotes {
+		var cmd tea.Cmd
+		f.notes, cmd = f.notes.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// focusCheckInField moves the form focus, focusing the notes input when it's reached
+func (m *Model) focusCheckInField(field int) tea.Cmd {
+	m.checkIn.field = field
+	if field == checkInNotes {
+		return m.checkIn.notes.Focus()
+	}
+	m.checkIn.notes.Blur()
+	return nil
+}
+
+// adjust changes the focused rating by delta, staying within range
+func (f *checkInForm) adjust(delta int) {
+	rating := f.mood
+	if f.field == checkInEnergy {
+		rating = f.energy
+	}
+	f.set(min(model.MaxRating, max(model.MinRating, rating+delta)))
+}
+
+// set replaces the focused rating
+func (f *checkInForm) set(rating int) {
+	switch f.field {
+	case checkInMood:
+		f.mood = rating
+	case checkInEnergy:
+		f.energy = rating
+	}
+}
+
+func (m Model) saveCheckIn(c model.CheckIn) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		return CheckInSavedMsg{Err: m.checkIns.Save(ctx, &c)}
+	}
+}
+
+func (m Model) skipCheckIn() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		return CheckInSavedMsg{Err: m.checkIns.Skip(ctx)}
+	}
+}
+
+// renderRating renders a 1-5 rating as dots with its label, e.g. "●●●●○ 4 good"
+func renderRating(rating int, labels []string, focused bool) string {
+	style := ui.MutedText
+	if focused {
+		style = lipgloss.NewStyle().Foreground(ui.Primary)
+	}
+	dots := strings.Repeat("●", rating) + strings.Repeat("○", model.MaxRating-rating)
+	return style.Render(dots) + fmt.Sprintf(" %d ", rating) + ui.MutedText.Render(labels[rating])
+}
+
+func (m Model) renderCheckInContent() string {
+	f := m.checkIn
+
+	row := func(field int, label, value string) string {
+		cursor := "  "
+		labelStyle := ui.NormalItem
+		if f.field == field {
+			cursor = "> "
+			labelStyle = ui.SelectedItem
+		}
+		return cursor + labelStyle.Render(fmt.Sprintf("%-7s", label)) + " " + value
+	}
+
+	save := key.NewBinding(key.WithKeys(m.keys.Select.Keys()...), key.WithHelp(m.keys.Select.Help().Key, "save"))
+	skip := key.NewBinding(key.WithKeys(m.keys.Back.Keys()...), key.WithHelp(m.keys.Back.Help().Key, "skip today"))
+	if m.todayCheckIn != nil {
+		skip = m.keys.Back
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		ui.Subtitle.Render("Daily check-in"),
+		"How are you feeling today?",
+		"",
+		row(checkInMood, "Mood", renderRating(f.mood, moodLabels, f.field == checkInMood)),
+		row(checkInEnergy, "Energy", renderRating(f.energy, energyLabels, f.field == checkInEnergy)),
+		row(checkInNotes, "Note", f.notes.View()),
+		"",
+		ui.HelpLine(m.keys.NextField, save, skip),
+	)
+}
+
+// renderCheckInSummary renders today's ratings for the header, e.g. "mood 4 · energy 3"
+func (m Model) renderCheckInSummary() string {
+	c := m.todayCheckIn
+	if c == nil {
+		return ""
+	}
+	return fmt.Sprintf("mood %d · energy %d", c.Mood, c.Energy)
+}
+
+// checkInHelpSections returns the bindings for the check-in form
+func (m Model) checkInHelpSections() []ui.HelpSection {
+	rate := key.NewBinding(
+		key.WithKeys(append(m.keys.Left.Keys(), m.keys.Right.Keys()...)...),
+		key.WithHelp(m.keys.Left.Help().Key+"/"+m.keys.Right.Help().Key+"/1-5", "rate"),
+	)
+	return []ui.HelpSection{
+		{Title: "Check-in", Bindings: []key.Binding{
+			rate, m.keys.NextField, m.keys.PrevField, m.keys.Select, m.keys.Save, m.keys.Back,
+		}},
+	}
+}
@@ -0,0 +1,219 @@
+package today
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+)
+
+// JournalLoadedMsg is sent when the completion notes are loaded
+type JournalLoadedMsg struct {
+	Entries []JournalEntry
+	Err     error
+}
+
+func (m Model) loadJournal() tea.Msg {
+	ctx, cancel := db.WithTimeout(context.Background())
+	defer cancel()
+	entries, err := m.service.Journal(ctx)
+	return JournalLoadedMsg{Entries: entries, Err: err}
+}
+
+// openJournal switches to the journal and loads the notes
+func (m *Model) openJournal() tea.Cmd {
+	m.mode = modeJournal
+	m.journalList = ui.ScrollList{}
+	return m.loadJournal
+}
+
+// handleJournalKey handles keys while browsing the journal
+func (m Model) handleJournalKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.journalSearch.Editing() {
+		changed, cmd := m.journalSearch.Update(msg, m.keys)
+		if changed {
+			m.journalList.Cursor = 0
+		}
+		m.applyJournalSearch()
+		return m, cmd
+	}
+
+	if m.journalList.HandleKey(msg, m.keys, len(m.journalShown)) {
+		m.syncJournalScroll()
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Filter):
+		cmd := m.journalSearch.Open()
+		m.syncJournalScroll()
+		return m, cmd
+	case key.Matches(msg, m.keys.Sort):
+		m.journalByHabit = !m.journalByHabit
+		m.journalList.Cursor = 0
+		m.applyJournalSearch()
+	case key.Matches(msg, m.keys.Back):
+		if m.journalSearch.Query() != "" {
+			m.journalSearch.SetQuery("")
+			m.applyJournalSearch()
+			return m, nil
+		}
+		m.mode = modeList
+	case key.Matches(msg, m.keys.Journal):
+		m.mode = modeList
+	}
+	return m, nil
+}
+
+// applyJournalSearch recomputes which notes are shown and in what order.
+// Every word of the search must appear in the note or the habit name.
+func (m *Model) applyJournalSearch() {
+	terms := strings.Fields(strings.ToLower(m.journalSearch.Query()))
+
+	m.journalShown = m.journalShown[:0]
+	for i, e := range m.journal {
+		text := strings.ToLower(e.Notes + " " + e.HabitName)
+		matches := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			m.journalShown = append(m.journalShown, i)
+		}
+	}
+
+	// Notes load newest first, which is the by-date order
+	if m.journalByHabit {
+		sort.SliceStable(m.journalShown, func(i, j int) bool {
+			a, b := m.journal[m.journalShown[i]], m.journal[m.journalShown[j]]
+			if a.HabitName != b.HabitName {
+				return strings.ToLower(a.HabitName) < strings.ToLower(b.HabitName)
+			}
+			return a.HabitID < b.HabitID
+		})
+	}
+
+	m.journalList.Clamp(len(m.journalShown))
+	m.syncJournalScroll()
+}
+
+// journalGroup returns the heading a note is listed under
+func (m Model) journalGroup(e JournalEntry) string {
+	if m.journalByHabit {
+		if e.HabitEmoji != "" {
+			return e.HabitEmoji + " " + e.HabitName
+		}
+		return e.HabitName
+	}
+	return e.CompletedAt.Format("Monday, January 2, 2006")
+}
+
+// journalRows lays out the shown notes under their headings. spans holds
+// the first and last row of each note so the selected one can be kept in view.
+func (m Model) journalRows() (rows []string, spans [][2]int) {
+	width := m.panelWidth
+	heading := lipgloss.NewStyle().Bold(true).Foreground(ui.Primary)
+
+	for i, index := range m.journalShown {
+		e := m.journal[index]
+		group := m.journalGroup(e)
+		if i == 0 || group != m.journalGroup(m.journal[m.journalShown[i-1]]) {
+			if i > 0 {
+				rows = append(rows, "")
+			}
+			rows = append(rows, heading.Render(group))
+		}
+
+		cursor := "  "
+		labelStyle := ui.NormalItem
+		if i == m.journalList.Cursor {
+			cursor = "> "
+			labelStyle = ui.SelectedItem
+		}
+
+		// By date, label each note with its habit; by habit, with its date
+		label := e.CompletedAt.Format("Jan 2")
+		if !m.journalByHabit {
+			label = e.HabitName
+			if e.HabitEmoji != "" {
+				label = e.HabitEmoji + " " + label
+			}
+		}
+
+		first := len(rows)
+		lines := strings.Split(e.Notes, "\n")
+		rows = append(rows, truncate(cursor+labelStyle.Render(label)+"  "+lines[0], width))
+		for _, line := range lines[1:] {
+			rows = append(rows, truncate("    "+line, width))
+		}
+		spans = append(spans, [2]int{first, len(rows) - 1})
+	}
+	return rows, spans
+}
+
+// syncJournalScroll sizes the journal to the panel, leaving room for the
+// title, search and key hints, and keeps the selected note in view
+func (m *Model) syncJournalScroll() {
+	m.journalList.Height = 0
+	if m.panelHeight > 0 {
+		search := lipgloss.Height(m.journalSearch.View(nil, 0, 0)) - 1
+		m.journalList.Height = max(1, m.panelHeight-4-search)
+	}
+
+	rows, spans := m.journalRows()
+	if m.journalList.Cursor < len(spans) {
+		span := spans[m.journalList.Cursor]
+		m.journalList.Follow(span[0], span[1], len(rows))
+	}
+}
+
+// truncate cuts s to width cells, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if width <= 0 || lipgloss.Width(s) <= width {
+		return s
+	}
+	return lipgloss.NewStyle().MaxWidth(width-1).Render(s) + "…"
+}
+
+func (m Model) renderJournalContent() string {
+	order := "by date"
+	if m.journalByHabit {
+		order = "by habit"
+	}
+	s := ui.Subtitle.Render("Journal · "+order) + "\n"
+	s += m.journalSearch.View(nil, len(m.journalShown), len(m.journal))
+
+	switch {
+	case len(m.journal) == 0:
+		s += ui.MutedText.Render(fmt.Sprintf("No notes yet. Press '%s' on a habit to complete it with a note.", m.keys.Note.Help().Key)) + "\n"
+	case len(m.journalShown) == 0:
+		s += ui.MutedText.Render("No notes match the search.") + "\n"
+	default:
+		rows, _ := m.journalRows()
+		s += m.journalList.Render(rows)
+	}
+
+	s += "\n" + ui.HelpLine(m.keys.Filter, m.keys.Sort, m.keys.Back)
+	return s
+}
+
+// journalHelpSections returns the bindings for the journal
+func (m Model) journalHelpSections() []ui.HelpSection {
+	if m.journalSearch.Editing() {
+		return []ui.HelpSection{{Title: "Search", Bindings: ui.FilterHelp(m.keys)}}
+	}
+	return []ui.HelpSection{
+		{Title: "Journal", Bindings: []key.Binding{
+			m.keys.Up, m.keys.Down, m.keys.PageUp, m.keys.PageDown, m.keys.Filter, m.keys.Sort, m.keys.Journal, m.keys.Back,
+		}},
+	}
+}
@@ -0,0 +1,96 @@
+package today
+
+import (
+	"math/rand/v2"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+)
+
+const (
+	benchHabits = 20
+	benchYears  = 10
+
+	// maxLoadTime is how long loading the Today tab may take on average
+	maxLoadTime = 50 * time.Millisecond
+)
+
+// seedHistory fills a new database with habits completed on most days over
+// the past years, some of them more than once a day
+func seedHistory(tb testing.TB, habits, years int) *db.DB {
+	tb.Helper()
+	database, err := db.Open(filepath.Join(tb.TempDir(), "habit.db"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { database.Close() })
+
+	tx, err := database.Begin()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	start := now.AddDate(-years, 0, 0)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range habits {
+		result, err := tx.Exec(
+			`INSERT INTO habits (name, target_per_day, position, created_at) VALUES (?, ?, ?, ?)`,
+			"Habit", 1+i%3, i, start.Format("2006-01-02 15:04:05"),
+		)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		id, _ := result.LastInsertId()
+
+		for d := start; !d.After(now); d = d.AddDate(0, 0, 1) {
+			if rng.IntN(4) == 0 {
+				continue
+			}
+			for range 1 + rng.IntN(2) {
+				_, err := tx.Exec(
+					`INSERT INTO completions (habit_id, completed_at) VALUES (?, ?)`,
+					id, d.Format("2006-01-02"),
+				)
+				if err != nil {
+					tb.Fatal(err)
+				}
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		tb.Fatal(err)
+	}
+	if _, err := database.Reindex(tb.Context()); err != nil {
+		tb.Fatal(err)
+	}
+	return database
+}
+
+// BenchmarkLoadToday loads the Today tab over ten years of completions
+func BenchmarkLoadToday(b *testing.B) {
+	database := seedHistory(b, benchHabits, benchYears)
+	m := New(database, ui.DefaultKeyMap)
+
+	b.ResetTimer()
+	start := time.Now()
+	for b.Loop() {
+		msg := m.loadData().(TodayLoadedMsg)
+		if msg.Err != nil {
+			b.Fatal(msg.Err)
+		}
+		if len(msg.Habits) != benchHabits {
+			b.Fatalf("loaded %d habits, want %d", len(msg.Habits), benchHabits)
+		}
+	}
+
+	perLoad := time.Since(start) / time.Duration(b.N)
+	b.ReportMetric(float64(perLoad.Microseconds())/1000, "ms/load")
+	if perLoad > maxLoadTime {
+		b.Errorf("Today tab took %v to load, want under %v", perLoad, maxLoadTime)
+	}
+}
@@ -0,0 +1,169 @@
+package today
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+)
+
+// NoteSavedMsg is sent when a habit was completed with a note, or today's
+// note was changed
+type NoteSavedMsg struct {
+	HabitID int64
+	Err     error
+}
+
+// NoteEditedMsg is sent when the external editor exits with the note text
+type NoteEditedMsg struct {
+	Text string
+	Err  error
+}
+
+// openNote starts writing a note for the selected habit. A habit that is
+// already done today gets its latest note edited; otherwise saving the
+// note also completes the habit.
+func (m *Model) openNote() tea.Cmd {
+	e, ok := m.selected()
+	if !ok || e.habit == -1 {
+		return nil
+	}
+	habit := m.habits[e.habit]
+
+	m.note = textarea.New()
+	m.note.Placeholder = "How did it go?"
+	m.note.ShowLineNumbers = false
+	m.note.CharLimit = 2000
+	m.note.SetWidth(m.noteWidth())
+	m.note.SetHeight(5)
+	if habit.CompletedToday {
+		m.note.SetValue(habit.NoteToday)
+	}
+	m.noteHabit = habit
+	m.mode = modeNote
+	return m.note.Focus()
+}
+
+// handleNoteKey handles keys while writing a note
+func (m Model) handleNoteKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case ui.MatchesNonText(msg, m.keys.Back):
+		m.mode = modeList
+		return m, nil
+	case ui.MatchesNonText(msg, m.keys.Save):
+		m.mode = modeList
+		return m, m.saveNote(m.noteHabit, strings.TrimSpace(m.note.Value()))
+	case ui.MatchesNonText(msg, m.keys.Editor):
+		return m, m.openEditor()
+	}
+
+	var cmd tea.Cmd
+	m.note, cmd = m.note.Update(msg)
+	return m, cmd
+}
+
+// noteWidth returns the width of the note text area, leaving room for its border
+func (m Model) noteWidth() int {
+	return max(20, min(60, m.panelWidth-4))
+}
+
+func (m Model) saveNote(habit HabitWithStatus, notes string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		var err error
+		if habit.CompletedToday {
+			err = m.service.SetTodayNote(ctx, habit.ID, notes)
+		} else {
+			err = m.service.CompleteWithNotes(ctx, habit.ID, notes)
+		}
+		return NoteSavedMsg{HabitID: habit.ID, Err: err}
+	}
+}
+
+// openEditor suspends the TUI and edits the note in $VISUAL or $EDITOR,
+// falling back to vi
+func (m Model) openEditor() tea.Cmd {
+	f, err := os.CreateTemp("", "hbt-note-*.txt")
+	if err != nil {
+		return func() tea.Msg { return NoteEditedMsg{Err: err} }
+	}
+	path := f.Name()
+	_, err = f.WriteString(m.note.Value())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return NoteEditedMsg{Err: err} }
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := append(strings.Fields(editor), path)
+
+	return tea.ExecProcess(exec.Command(args[0], args[1:]...), func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return NoteEditedMsg{Err: err}
+		}
+		data, err := os.ReadFile(path)
+		return NoteEditedMsg{Text: strings.TrimRight(string(data), "\n"), Err: err}
+	})
+}
+
+// handleEdited puts the text from the external editor back in the note
+func (m Model) handleEdited(msg NoteEditedMsg) Model {
+	if m.mode != modeNote {
+		return m
+	}
+	if msg.Err != nil {
+		m.noteErr = msg.Err
+		return m
+	}
+	m.noteErr = nil
+	m.note.SetValue(msg.Text)
+	return m
+}
+
+func (m Model) renderNoteContent() string {
+	title := m.noteHabit.Name
+	if m.noteHabit.Emoji != "" {
+		title = m.noteHabit.Emoji + " " + title
+	}
+
+	hint := "Saving also marks the habit done today."
+	if m.noteHabit.CompletedToday {
+		hint = "Editing the note on today's completion."
+	}
+
+	lines := []string{
+		ui.Subtitle.Render("Note for " + title),
+		ui.FormInputFocused.Render(m.note.View()),
+		ui.MutedText.Render(hint),
+	}
+	if m.noteErr != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.Danger).Render("Editor: "+m.noteErr.Error()))
+	}
+	lines = append(lines, "", ui.HelpLine(m.keys.Save, m.keys.Editor, m.keys.Back))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// noteHelpSections returns the bindings for the note editor
+func (m Model) noteHelpSections() []ui.HelpSection {
+	return []ui.HelpSection{
+		{Title: "Note", Bindings: []key.Binding{m.keys.Save, m.keys.Editor, m.keys.Back}},
+	}
+}
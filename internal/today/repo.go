@@ -1,6 +1,8 @@
 package today
 
 import (
+	"context"
+	"database/sql"
 	"time"
 
 	"github.com/vittolewerissa/hbt/internal/shared/db"
@@ -18,17 +20,17 @@ func NewRepository(database *db.DB) *Repository {
 }
 
 // IsCompletedOn checks if a habit is completed on a specific date
-func (r *Repository) IsCompletedOn(habitID int64, date time.Time) (bool, error) {
-	count, err := r.CountCompletionsOn(habitID, date)
+func (r *Repository) IsCompletedOn(ctx context.Context, habitID int64, date time.Time) (bool, error) {
+	count, err := r.CountCompletionsOn(ctx, habitID, date)
 	return count > 0, err
 }
 
 // CountCompletionsOn returns the number of completions for a habit on a specific date
-func (r *Repository) CountCompletionsOn(habitID int64, date time.Time) (int, error) {
+func (r *Repository) CountCompletionsOn(ctx context.Context, habitID int64, date time.Time) (int, error) {
 	query := `SELECT COUNT(*) FROM completions WHERE habit_id = ? AND completed_at = ?`
 	dateStr := date.Format("2006-01-02")
 	var count int
-	err := r.db.QueryRow(query, habitID, dateStr).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, habitID, dateStr).Scan(&count)
 	return count, err
 }
 
@@ -36,27 +38,61 @@ func (r *Repository) CountCompletionsOn(habitID int64, date time.Time) (int, err
 // Note: Uses INSERT (not INSERT OR REPLACE) to allow multiple completions per day
 // For databases created before this feature, the UNIQUE constraint may still exist
 // and will cause an error on duplicate completions - this is expected behavior
-func (r *Repository) Complete(habitID int64, date time.Time, notes string) error {
+func (r *Repository) Complete(ctx context.Context, habitID int64, date time.Time, notes string) error {
 	query := `INSERT INTO completions (habit_id, completed_at, notes) VALUES (?, ?, ?)`
 	dateStr := date.Format("2006-01-02")
-	_, err := r.db.Exec(query, habitID, dateStr, notes)
-	return err
+	return r.db.LogChange(ctx, db.EntityCompletion, nil, func(tx *sql.Tx) (any, error) {
+		result, err := tx.ExecContext(ctx, query, habitID, dateStr, notes)
+		if err != nil {
+			return nil, err
+		}
+		if err := cacheCompleted(ctx, tx, habitID, date); err != nil {
+			return nil, err
+		}
+		return result.LastInsertId()
+	})
 }
 
-// Uncomplete removes one completion for a date (removes the most recent one)
-func (r *Repository) Uncomplete(habitID int64, date time.Time) error {
-	query := `DELETE FROM completions WHERE id = (
-		SELECT id FROM completions
+// latestOn returns the most recent completion of a habit on a date inside
+// tx, or sql.ErrNoRows if there is none
+func latestOn(ctx context.Context, tx *sql.Tx, habitID int64, date time.Time) (id int64, notes string, err error) {
+	query := `
+		SELECT id, notes FROM completions
 		WHERE habit_id = ? AND completed_at = ?
 		ORDER BY id DESC LIMIT 1
-	)`
-	dateStr := date.Format("2006-01-02")
-	_, err := r.db.Exec(query, habitID, dateStr)
-	return err
+	`
+	var n sql.NullString
+	err = tx.QueryRowContext(ctx, query, habitID, date.Format("2006-01-02")).Scan(&id, &n)
+	return id, n.String, err
+}
+
+// Uncomplete removes one completion for a date (removes the most recent one)
+// and returns its notes
+func (r *Repository) Uncomplete(ctx context.Context, habitID int64, date time.Time) (string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	id, notes, err := latestOn(ctx, tx, habitID, date)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := r.db.ExecChange(ctx, tx, db.EntityCompletion, id, `DELETE FROM completions WHERE id = ?`, id); err != nil {
+		return "", err
+	}
+	if err := cacheUncompleted(ctx, tx, habitID, date); err != nil {
+		return "", err
+	}
+	return notes, tx.Commit()
 }
 
 // GetCompletionsInRange returns all completions for a habit in a date range
-func (r *Repository) GetCompletionsInRange(habitID int64, start, end time.Time) ([]model.Completion, error) {
+func (r *Repository) GetCompletionsInRange(ctx context.Context, habitID int64, start, end time.Time) ([]model.Completion, error) {
 	query := `
 		SELECT id, habit_id, completed_at, notes
 		FROM completions
@@ -66,7 +102,7 @@ func (r *Repository) GetCompletionsInRange(habitID int64, start, end time.Time)
 	startStr := start.Format("2006-01-02")
 	endStr := end.Format("2006-01-02")
 
-	rows, err := r.db.Query(query, habitID, startStr, endStr)
+	rows, err := r.db.QueryContext(ctx, query, habitID, startStr, endStr)
 	if err != nil {
 		return nil, err
 	}
@@ -85,118 +121,130 @@ func (r *Repository) GetCompletionsInRange(habitID int64, start, end time.Time)
 	return completions, rows.Err()
 }
 
-// CountCompletionsThisWeek returns the number of completions this week
-func (r *Repository) CountCompletionsThisWeek(habitID int64) (int, error) {
-	// Get start of this week (Monday)
-	now := time.Now()
-	weekday := int(now.Weekday())
-	if weekday == 0 {
-		weekday = 7 // Sunday is day 7
+// CompletionDays returns the days each active habit was completed, oldest
+// first, with how many times it was completed on each. Archived habits are
+// returned instead when archived is set. It reads every habit's whole history
+// in one query, grouped so there is at most one row per habit and day; the
+// app reads streaks from CachedStats instead.
+func (r *Repository) CompletionDays(ctx context.Context, archived bool) (map[int64][]DayCount, error) {
+	condition := "h.archived_at IS NULL"
+	if archived {
+		condition = "h.archived_at IS NOT NULL"
 	}
-	startOfWeek := now.AddDate(0, 0, -(weekday - 1))
-	startOfWeek = time.Date(startOfWeek.Year(), startOfWeek.Month(), startOfWeek.Day(), 0, 0, 0, 0, time.Local)
+	return r.completionDays(ctx, condition)
+}
 
-	query := `SELECT COUNT(*) FROM completions WHERE habit_id = ? AND completed_at >= ?`
-	startStr := startOfWeek.Format("2006-01-02")
-	var count int
-	err := r.db.QueryRow(query, habitID, startStr).Scan(&count)
-	return count, err
+// RecentCompletionDays is CompletionDays for active habits, limited to the
+// days from since onwards
+func (r *Repository) RecentCompletionDays(ctx context.Context, since time.Time) (map[int64][]DayCount, error) {
+	return r.completionDays(ctx, "h.archived_at IS NULL AND c.completed_at >= ?", since.Format("2006-01-02"))
 }
 
-// CalculateCurrentStreak calculates the current streak for a daily habit
-func (r *Repository) CalculateCurrentStreak(habitID int64) (int, error) {
+// completionDays returns completion counts per habit and day for the
+// completions matching condition
+func (r *Repository) completionDays(ctx context.Context, condition string, args ...any) (map[int64][]DayCount, error) {
 	query := `
-		SELECT completed_at FROM completions
-		WHERE habit_id = ?
-		ORDER BY completed_at DESC
+		SELECT c.habit_id, substr(c.completed_at, 1, 10) AS day, COUNT(*)
+		FROM completions c
+		JOIN habits h ON c.habit_id = h.id
+		WHERE ` + condition + `
+		GROUP BY c.habit_id, day
+		ORDER BY c.habit_id, day
 	`
-	rows, err := r.db.Query(query, habitID)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	var streak int
-	today := time.Now().Format("2006-01-02")
-	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
-	expectedDate := today
-
+	days := make(map[int64][]DayCount)
 	for rows.Next() {
-		var dateStr string
-		if err := rows.Scan(&dateStr); err != nil {
-			return 0, err
+		var habitID int64
+		var d DayCount
+		if err := rows.Scan(&habitID, &d.Date, &d.Count); err != nil {
+			return nil, err
 		}
+		days[habitID] = append(days[habitID], d)
+	}
+	return days, rows.Err()
+}
 
-		// First completion must be today or yesterday
-		if streak == 0 {
-			if dateStr != today && dateStr != yesterday {
-				return 0, nil
-			}
-			expectedDate = dateStr
-		}
+// NotesOn returns the note on each habit's most recent completion on a date,
+// keyed by habit ID
+func (r *Repository) NotesOn(ctx context.Context, date time.Time) (map[int64]string, error) {
+	query := `
+		SELECT habit_id, notes FROM completions
+		WHERE id IN (
+			SELECT MAX(id) FROM completions WHERE completed_at = ? GROUP BY habit_id
+		)
+	`
+	rows, err := r.db.QueryContext(ctx, query, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		if dateStr == expectedDate {
-			streak++
-			// Calculate previous day
-			d, _ := time.Parse("2006-01-02", expectedDate)
-			expectedDate = d.AddDate(0, 0, -1).Format("2006-01-02")
-		} else if dateStr < expectedDate {
-			// Gap found, streak broken
-			break
+	notes := make(map[int64]string)
+	for rows.Next() {
+		var habitID int64
+		var n sql.NullString
+		if err := rows.Scan(&habitID, &n); err != nil {
+			return nil, err
 		}
-		// Skip duplicate dates
+		notes[habitID] = n.String
 	}
+	return notes, rows.Err()
+}
 
-	return streak, rows.Err()
+// SetNoteOn replaces the note on the most recent completion of a habit on a date
+func (r *Repository) SetNoteOn(ctx context.Context, habitID int64, date time.Time, notes string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	id, _, err := latestOn(ctx, tx, habitID, date)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.db.ExecChange(ctx, tx, db.EntityCompletion, id, `UPDATE completions SET notes = ? WHERE id = ?`, notes, id); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// CalculateBestStreak calculates the best streak ever for a habit
-func (r *Repository) CalculateBestStreak(habitID int64) (int, error) {
+// ListNotes returns every completion that has a note, newest first, with
+// the name and emoji of its habit. Archived habits are included.
+func (r *Repository) ListNotes(ctx context.Context) ([]JournalEntry, error) {
 	query := `
-		SELECT completed_at FROM completions
-		WHERE habit_id = ?
-		ORDER BY completed_at ASC
+		SELECT c.id, c.habit_id, c.completed_at, c.notes, h.name, h.emoji
+		FROM completions c
+		JOIN habits h ON c.habit_id = h.id
+		WHERE c.notes IS NOT NULL AND TRIM(c.notes) != ''
+		ORDER BY c.completed_at DESC, c.id DESC
 	`
-	rows, err := r.db.Query(query, habitID)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	var bestStreak, currentStreak int
-	var lastDate string
-
+	var entries []JournalEntry
 	for rows.Next() {
+		var e JournalEntry
 		var dateStr string
-		if err := rows.Scan(&dateStr); err != nil {
-			return 0, err
+		if err := rows.Scan(&e.ID, &e.HabitID, &dateStr, &e.Notes, &e.HabitName, &e.HabitEmoji); err != nil {
+			return nil, err
 		}
-
-		if lastDate == "" {
-			currentStreak = 1
-		} else {
-			last, _ := time.Parse("2006-01-02", lastDate)
-			curr, _ := time.Parse("2006-01-02", dateStr)
-
-			daysDiff := int(curr.Sub(last).Hours() / 24)
-
-			if daysDiff == 1 {
-				currentStreak++
-			} else if daysDiff > 1 {
-				if currentStreak > bestStreak {
-					bestStreak = currentStreak
-				}
-				currentStreak = 1
-			}
-			// daysDiff == 0 means duplicate, ignore
+		// DATE columns may come back with a time part
+		if len(dateStr) >= len("2006-01-02") {
+			e.CompletedAt, _ = time.Parse("2006-01-02", dateStr[:len("2006-01-02")])
 		}
-
-		lastDate = dateStr
+		entries = append(entries, e)
 	}
-
-	if currentStreak > bestStreak {
-		bestStreak = currentStreak
-	}
-
-	return bestStreak, rows.Err()
+	return entries, rows.Err()
 }
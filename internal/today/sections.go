@@ -0,0 +1,141 @@
+package today
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+	"github.com/vittolewerissa/hbt/internal/shared/ui"
+)
+
+// uncategorized is the section ID used for habits without a category
+const uncategorized int64 = 0
+
+// entry is one row of the Today list: a section header or a habit
+type entry struct {
+	section int64 // category ID, or uncategorized
+	habit   int   // index into habits, or -1 for the section header
+}
+
+// SectionsSavedMsg is sent when the collapsed sections have been stored
+type SectionsSavedMsg struct {
+	Err error
+}
+
+// sectionID returns the section a habit is listed under
+func sectionID(h HabitWithStatus) int64 {
+	if h.Category == nil {
+		return uncategorized
+	}
+	return h.Category.ID
+}
+
+// buildEntries groups the visible habits into sections, leaving out the
+// habits of collapsed sections. Sections are only used when sorting by
+// category; other sort modes list habits flat.
+func (m *Model) buildEntries() {
+	m.entries = m.entries[:0]
+	if m.sortMode != model.SortCategory {
+		for _, index := range m.visible {
+			m.entries = append(m.entries, entry{section: sectionID(m.habits[index]), habit: index})
+		}
+		return
+	}
+
+	for i, index := range m.visible {
+		section := sectionID(m.habits[index])
+		if i == 0 || section != sectionID(m.habits[m.visible[i-1]]) {
+			m.entries = append(m.entries, entry{section: section, habit: -1})
+		}
+		if !m.collapsed[section] {
+			m.entries = append(m.entries, entry{section: section, habit: index})
+		}
+	}
+}
+
+// selected returns the entry under the cursor
+func (m Model) selected() (entry, bool) {
+	if m.list.Cursor < 0 || m.list.Cursor >= len(m.entries) {
+		return entry{}, false
+	}
+	return m.entries[m.list.Cursor], true
+}
+
+// toggleSection collapses or expands a section, keeping the cursor on its header
+func (m *Model) toggleSection(section int64) tea.Cmd {
+	if m.collapsed == nil {
+		m.collapsed = make(map[int64]bool)
+	}
+	m.collapsed[section] = !m.collapsed[section]
+	m.buildEntries()
+
+	for i, e := range m.entries {
+		if e.section == section && e.habit == -1 {
+			m.list.Cursor = i
+			break
+		}
+	}
+	m.list.Clamp(len(m.entries))
+	m.syncScroll()
+
+	collapsed := make(map[int64]bool, len(m.collapsed))
+	for id, ok := range m.collapsed {
+		collapsed[id] = ok
+	}
+	return func() tea.Msg {
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		return SectionsSavedMsg{Err: m.service.SetCollapsedSections(ctx, collapsed)}
+	}
+}
+
+// renderSectionHeader renders a category header with its progress, e.g. "▾ 💪 Health 2/4"
+func (m Model) renderSectionHeader(index int, e entry) string {
+	cursor := "  "
+	if index == m.list.Cursor {
+		cursor = "> "
+	}
+
+	arrow := "▾"
+	if m.collapsed[e.section] {
+		arrow = "▸"
+	}
+
+	// Progress counts the visible habits due today in this section
+	var done, due int
+	var title string
+	style := lipgloss.NewStyle().Bold(true)
+	for _, i := range m.visible {
+		h := m.habits[i]
+		if sectionID(h) != e.section {
+			continue
+		}
+		if title == "" {
+			if h.Category != nil {
+				title = h.Category.Name
+				if h.Category.Emoji != "" {
+					title = h.Category.Emoji + " " + title
+				}
+				style = ui.CategoryStyle(style, h.Category.Color)
+			} else {
+				title = "Uncategorized"
+				style = style.Foreground(ui.Muted)
+			}
+		}
+		if h.IsDue {
+			due++
+			if h.CompletedToday {
+				done++
+			}
+		}
+	}
+
+	line := cursor + style.Render(arrow+" "+title)
+	if due > 0 {
+		line += " " + ui.MutedText.Render(fmt.Sprintf("%d/%d", done, due))
+	}
+	return line
+}
@@ -1,24 +1,33 @@
 package today
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/vittolewerissa/hbt/internal/settings"
+	"github.com/vittolewerissa/hbt/internal/shared/clock"
 	"github.com/vittolewerissa/hbt/internal/shared/db"
 	"github.com/vittolewerissa/hbt/internal/shared/model"
 )
 
 // Service handles today's habits logic
 type Service struct {
-	db   *db.DB
-	repo *Repository
+	db    *db.DB
+	repo  *Repository
+	clock clock.Clock
 }
 
 // NewService creates a new today service
 func NewService(database *db.DB) *Service {
 	return &Service{
-		db:   database,
-		repo: NewRepository(database),
+		db:    database,
+		repo:  NewRepository(database),
+		clock: database.Clock,
 	}
 }
 
@@ -31,39 +40,58 @@ type HabitWithStatus struct {
 	BestStreak          int
 	CompletionsThisWeek int
 	IsDue               bool
+	NoteToday           string // note on today's latest completion
+}
+
+// JournalEntry is a completion note with the habit it belongs to
+type JournalEntry struct {
+	model.Completion
+	HabitName  string
+	HabitEmoji string
 }
 
 // GetHabitsForToday returns all habits with their status for today
-func (s *Service) GetHabitsForToday() ([]HabitWithStatus, error) {
+func (s *Service) GetHabitsForToday(ctx context.Context) ([]HabitWithStatus, error) {
+	today := s.clock.Now()
+
+	// Load the status of every habit up front rather than querying per habit
+	summaries, err := s.summaries(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.repo.NotesOn(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all active habits
 	query := `
 		SELECT h.id, h.name, h.description, h.emoji, h.category_id, h.frequency_type,
-		       h.frequency_value, h.target_per_day, h.created_at, h.archived_at,
-		       c.id, c.name, c.color, c.emoji
+		       h.frequency_value, h.target_per_day, h.position, h.created_at, h.archived_at,
+		       c.id, c.name, c.color, c.emoji, c.position
 		FROM habits h
 		LEFT JOIN categories c ON h.category_id = c.id
 		WHERE h.archived_at IS NULL
-		ORDER BY h.name
+		ORDER BY c.position NULLS LAST, c.name, h.position, h.id
 	`
-	rows, err := s.db.Query(query)
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	today := time.Now()
 	var habits []HabitWithStatus
 
 	for rows.Next() {
 		var h model.Habit
-		var categoryID, catID sql.NullInt64
+		var categoryID, catID, catPosition sql.NullInt64
 		var catName, catColor, catEmoji sql.NullString
 		var archivedAt sql.NullTime
 
 		err := rows.Scan(
 			&h.ID, &h.Name, &h.Description, &h.Emoji, &categoryID, &h.FrequencyType,
-			&h.FrequencyValue, &h.TargetPerDay, &h.CreatedAt, &archivedAt,
-			&catID, &catName, &catColor, &catEmoji,
+			&h.FrequencyValue, &h.TargetPerDay, &h.Position, &h.CreatedAt, &archivedAt,
+			&catID, &catName, &catColor, &catEmoji, &catPosition,
 		)
 		if err != nil {
 			return nil, err
@@ -77,53 +105,173 @@ func (s *Service) GetHabitsForToday() ([]HabitWithStatus, error) {
 		}
 		if catID.Valid {
 			h.Category = &model.Category{
-				ID:    catID.Int64,
-				Name:  catName.String,
-				Color: catColor.String,
-				Emoji: catEmoji.String,
+				ID:       catID.Int64,
+				Name:     catName.String,
+				Color:    catColor.String,
+				Emoji:    catEmoji.String,
+				Position: int(catPosition.Int64),
 			}
 		}
 
-		// Get completion status
-		completionsToday, _ := s.repo.CountCompletionsOn(h.ID, today)
-		completionsThisWeek, _ := s.repo.CountCompletionsThisWeek(h.ID)
-		currentStreak, _ := s.repo.CalculateCurrentStreak(h.ID)
-		bestStreak, _ := s.repo.CalculateBestStreak(h.ID)
+		summary := summaries[h.ID]
+		h.CurrentStreak = summary.CurrentStreak
+		h.BestStreak = summary.BestStreak
 
 		status := HabitWithStatus{
 			Habit:               h,
-			CompletedToday:      completionsToday >= h.TargetPerDay,
-			CompletionsToday:    completionsToday,
-			CurrentStreak:       currentStreak,
-			BestStreak:          bestStreak,
-			CompletionsThisWeek: completionsThisWeek,
-			IsDue:               h.IsDueToday(completionsThisWeek),
+			CompletedToday:      summary.CompletionsToday >= h.TargetPerDay,
+			CompletionsToday:    summary.CompletionsToday,
+			CurrentStreak:       summary.CurrentStreak,
+			BestStreak:          summary.BestStreak,
+			CompletionsThisWeek: summary.CompletionsThisWeek,
+			IsDue:               h.IsDueToday(summary.CompletionsThisWeek),
+			NoteToday:           notes[h.ID],
 		}
 
 		habits = append(habits, status)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	mode, err := s.SortMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(habits, func(i, j int) bool {
+		return model.HabitLess(mode, &habits[i].Habit, &habits[j].Habit, habits[i].IsDue, habits[j].IsDue)
+	})
+	return habits, nil
+}
+
+// summaries works out the status of every active habit from this week's
+// completions and the cached streaks
+func (s *Service) summaries(ctx context.Context, now time.Time) (map[int64]Summary, error) {
+	days, err := s.repo.RecentCompletionDays(ctx, now.AddDate(0, 0, -7))
+	if err != nil {
+		return nil, err
+	}
+	cached, err := s.repo.CachedStats(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[int64]Summary, len(days))
+	for id, d := range days {
+		summary := Summarize(d, now)
+		summaries[id] = Summary{
+			CompletionsToday:    summary.CompletionsToday,
+			CompletionsThisWeek: summary.CompletionsThisWeek,
+		}
+	}
+	for id, c := range cached {
+		summary := summaries[id]
+		summary.CurrentStreak = c.CurrentStreak(now)
+		summary.BestStreak = c.BestStreak
+		summaries[id] = summary
+	}
+	return summaries, nil
+}
 
-	return habits, rows.Err()
+// SortMode returns the configured habit sort order
+func (s *Service) SortMode(ctx context.Context) (model.SortMode, error) {
+	return settings.NewService(s.db).SortMode(ctx)
+}
+
+// Toggle is one completion added or removed by ToggleCompletion
+type Toggle struct {
+	HabitID   int64
+	Date      time.Time
+	Completed bool   // whether a completion was added rather than removed
+	Notes     string // notes on the removed completion
 }
 
 // ToggleCompletion toggles the completion status for today
 // If there are any completions, it removes one. Otherwise, it adds one.
-func (s *Service) ToggleCompletion(habitID int64) (bool, error) {
-	today := time.Now()
-	count, err := s.repo.CountCompletionsOn(habitID, today)
+func (s *Service) ToggleCompletion(ctx context.Context, habitID int64) (Toggle, error) {
+	t := Toggle{HabitID: habitID, Date: s.clock.Now()}
+	count, err := s.repo.CountCompletionsOn(ctx, habitID, t.Date)
 	if err != nil {
-		return false, err
+		return t, err
 	}
 
 	if count > 0 {
-		err = s.repo.Uncomplete(habitID, today)
-		return false, err
+		t.Notes, err = s.repo.Uncomplete(ctx, habitID, t.Date)
+		return t, err
+	}
+	t.Completed = true
+	return t, s.repo.Complete(ctx, habitID, t.Date, "")
+}
+
+// UndoToggle reverses a toggle on the day it was made, putting back a
+// removed completion with its notes
+func (s *Service) UndoToggle(ctx context.Context, t Toggle) error {
+	if t.Completed {
+		_, err := s.repo.Uncomplete(ctx, t.HabitID, t.Date)
+		return err
+	}
+	return s.repo.Complete(ctx, t.HabitID, t.Date, t.Notes)
+}
+
+// RedoToggle applies a toggle again after it was undone
+func (s *Service) RedoToggle(ctx context.Context, t Toggle) error {
+	if t.Completed {
+		return s.repo.Complete(ctx, t.HabitID, t.Date, "")
 	}
-	err = s.repo.Complete(habitID, today, "")
-	return true, err
+	_, err := s.repo.Uncomplete(ctx, t.HabitID, t.Date)
+	return err
 }
 
 // CompleteWithNotes marks a habit as completed with notes
-func (s *Service) CompleteWithNotes(habitID int64, notes string) error {
-	return s.repo.Complete(habitID, time.Now(), notes)
+func (s *Service) CompleteWithNotes(ctx context.Context, habitID int64, notes string) error {
+	return s.repo.Complete(ctx, habitID, s.clock.Now(), notes)
+}
+
+// SetTodayNote replaces the note on today's latest completion of a habit
+func (s *Service) SetTodayNote(ctx context.Context, habitID int64, notes string) error {
+	return s.repo.SetNoteOn(ctx, habitID, s.clock.Now(), notes)
+}
+
+// Journal returns all completion notes, newest first
+func (s *Service) Journal(ctx context.Context) ([]JournalEntry, error) {
+	return s.repo.ListNotes(ctx)
+}
+
+// CollapsedSections returns the category IDs whose sections are collapsed in
+// the Today list. Uncategorized habits use ID 0.
+func (s *Service) CollapsedSections(ctx context.Context) (map[int64]bool, error) {
+	collapsed := make(map[int64]bool)
+	value, err := settings.NewService(s.db).Get(ctx, settings.KeyCollapsedSections)
+	if errors.Is(err, sql.ErrNoRows) {
+		return collapsed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			continue
+		}
+		collapsed[id] = true
+	}
+	return collapsed, nil
+}
+
+// SetCollapsedSections stores which Today sections are collapsed
+func (s *Service) SetCollapsedSections(ctx context.Context, collapsed map[int64]bool) error {
+	var ids []int64
+	for id, ok := range collapsed {
+		if ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return settings.NewService(s.db).Set(ctx, settings.KeyCollapsedSections, strings.Join(parts, ","))
 }
@@ -0,0 +1,144 @@
+package today
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vittolewerissa/hbt/internal/shared/db/dbtest"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
+)
+
+func TestGetHabitsForToday(t *testing.T) {
+	// Wednesday; the week started on Monday the 5th
+	now := time.Date(2026, 1, 7, 9, 0, 0, 0, time.Local)
+	archivedAt := now.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name       string
+		habit      model.Habit
+		dates      []string
+		wantDue    bool
+		wantDone   bool
+		wantToday  int
+		wantStreak int
+	}{
+		{
+			name:    "daily habit is always due",
+			habit:   model.Habit{FrequencyType: model.FreqDaily},
+			wantDue: true,
+		},
+		{
+			name:       "daily habit done today",
+			habit:      model.Habit{FrequencyType: model.FreqDaily},
+			dates:      []string{"2026-01-06", "2026-01-07"},
+			wantDue:    true,
+			wantDone:   true,
+			wantToday:  1,
+			wantStreak: 2,
+		},
+		{
+			name:       "target per day needs every completion",
+			habit:      model.Habit{FrequencyType: model.FreqDaily, TargetPerDay: 3},
+			dates:      []string{"2026-01-07", "2026-01-07"},
+			wantDue:    true,
+			wantToday:  2,
+			wantStreak: 1,
+		},
+		{
+			name:      "target per day met",
+			habit:     model.Habit{FrequencyType: model.FreqDaily, TargetPerDay: 2},
+			dates:     []string{"2026-01-07", "2026-01-07", "2026-01-07"},
+			wantDue:   true,
+			wantDone:  true,
+			wantToday: 3,
+			// Extra completions still make one day
+			wantStreak: 1,
+		},
+		{
+			name:  "weekly habit done this week isn't due",
+			habit: model.Habit{FrequencyType: model.FreqWeekly},
+			dates: []string{"2026-01-05"},
+		},
+		{
+			name:    "weekly habit done last week is due again",
+			habit:   model.Habit{FrequencyType: model.FreqWeekly},
+			dates:   []string{"2026-01-04"},
+			wantDue: true,
+		},
+		{
+			name:       "times per week short of the target is due",
+			habit:      model.Habit{FrequencyType: model.FreqTimesPerWeek, FrequencyValue: 3},
+			dates:      []string{"2026-01-02", "2026-01-03", "2026-01-05", "2026-01-06"},
+			wantDue:    true,
+			wantStreak: 2,
+		},
+		{
+			name:       "times per week at the target isn't due",
+			habit:      model.Habit{FrequencyType: model.FreqTimesPerWeek, FrequencyValue: 2},
+			dates:      []string{"2026-01-05", "2026-01-06"},
+			wantStreak: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := dbtest.Open(t, now)
+			tt.habit.Name = "Habit"
+			id := dbtest.AddHabit(t, database, tt.habit)
+			dbtest.Complete(t, database, id, tt.dates...)
+			// Archived habits are left out
+			dbtest.AddHabit(t, database, model.Habit{Name: "Archived", ArchivedAt: &archivedAt})
+
+			habits, err := NewService(database).GetHabitsForToday(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(habits) != 1 {
+				t.Fatalf("got %d habits, want 1", len(habits))
+			}
+			h := habits[0]
+			if h.IsDue != tt.wantDue {
+				t.Errorf("IsDue = %v, want %v", h.IsDue, tt.wantDue)
+			}
+			if h.CompletedToday != tt.wantDone {
+				t.Errorf("CompletedToday = %v, want %v", h.CompletedToday, tt.wantDone)
+			}
+			if h.CompletionsToday != tt.wantToday {
+				t.Errorf("CompletionsToday = %d, want %d", h.CompletionsToday, tt.wantToday)
+			}
+			if h.CurrentStreak != tt.wantStreak {
+				t.Errorf("CurrentStreak = %d, want %d", h.CurrentStreak, tt.wantStreak)
+			}
+		})
+	}
+}
+
+func TestToggleCompletionUsesClock(t *testing.T) {
+	now := time.Date(2025, 12, 31, 23, 59, 0, 0, time.Local)
+	database := dbtest.Open(t, now)
+	id := dbtest.AddHabit(t, database, model.Habit{Name: "Habit"})
+	s := NewService(database)
+
+	toggle, err := s.ToggleCompletion(t.Context(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !toggle.Completed {
+		t.Fatal("toggle removed a completion, want one added")
+	}
+	var date string
+	if err := database.QueryRow(`SELECT completed_at FROM completions WHERE habit_id = ?`, id).Scan(&date); err != nil {
+		t.Fatal(err)
+	}
+	if date[:10] != "2025-12-31" {
+		t.Errorf("completed on %s, want 2025-12-31", date)
+	}
+
+	// Toggling again on the same day removes it
+	if toggle, err = s.ToggleCompletion(t.Context(), id); err != nil {
+		t.Fatal(err)
+	}
+	if toggle.Completed {
+		t.Error("second toggle added a completion, want it removed")
+	}
+}
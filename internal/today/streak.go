@@ -0,0 +1,66 @@
+package today
+
+import "time"
+
+// DayCount is how many times a habit was completed on one day
+type DayCount struct {
+	Date  string // YYYY-MM-DD
+	Count int
+}
+
+// Summary is a habit's completion status worked out from its history
+type Summary struct {
+	CompletionsToday    int
+	CompletionsThisWeek int
+	CurrentStreak       int // consecutive days ending today or yesterday
+	BestStreak          int
+}
+
+// Summarize works out a habit's status relative to now in a single pass
+// over the days it was completed, oldest first
+func Summarize(days []DayCount, now time.Time) Summary {
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+
+	// Weeks start on Monday
+	weekday := int(now.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	startOfWeek := now.AddDate(0, 0, -(weekday - 1)).Format("2006-01-02")
+
+	var s Summary
+	var run int
+	var last time.Time
+	for i, d := range days {
+		if d.Date == today {
+			s.CompletionsToday = d.Count
+		}
+		if d.Date >= startOfWeek {
+			s.CompletionsThisWeek += d.Count
+		}
+
+		date, err := time.Parse("2006-01-02", d.Date)
+		if err != nil {
+			continue
+		}
+		if i > 0 && date.Equal(last.AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run = 1
+		}
+		last = date
+		if run > s.BestStreak {
+			s.BestStreak = run
+		}
+	}
+
+	// The streak is current only if the latest completion is recent enough
+	if len(days) > 0 {
+		latest := days[len(days)-1].Date
+		if latest == today || latest == yesterday {
+			s.CurrentStreak = run
+		}
+	}
+	return s
+}
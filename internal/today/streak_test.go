@@ -0,0 +1,186 @@
+package today
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // DST cases need zones that may not be installed
+)
+
+// days returns one completion on each date, and more on a date repeated
+func days(dates ...string) []DayCount {
+	var out []DayCount
+	for _, d := range dates {
+		if len(out) > 0 && out[len(out)-1].Date == d {
+			out[len(out)-1].Count++
+			continue
+		}
+		out = append(out, DayCount{Date: d, Count: 1})
+	}
+	return out
+}
+
+func TestSummarize(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Thursday, 8am
+	thursday := time.Date(2026, 1, 8, 8, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name string
+		days []DayCount
+		now  time.Time
+		want Summary
+	}{
+		{
+			name: "never completed",
+			now:  thursday,
+			want: Summary{},
+		},
+		{
+			name: "completed today",
+			days: days("2026-01-08"),
+			now:  thursday,
+			want: Summary{CompletionsToday: 1, CompletionsThisWeek: 1, CurrentStreak: 1, BestStreak: 1},
+		},
+		{
+			name: "several completions on one day count as one day of a streak",
+			days: days("2026-01-06", "2026-01-07", "2026-01-07", "2026-01-08", "2026-01-08", "2026-01-08"),
+			now:  thursday,
+			want: Summary{CompletionsToday: 3, CompletionsThisWeek: 6, CurrentStreak: 3, BestStreak: 3},
+		},
+		{
+			name: "streak ending yesterday is still current",
+			days: days("2026-01-05", "2026-01-06", "2026-01-07"),
+			now:  thursday,
+			want: Summary{CompletionsThisWeek: 3, CurrentStreak: 3, BestStreak: 3},
+		},
+		{
+			name: "streak ending two days ago is broken",
+			days: days("2026-01-04", "2026-01-05", "2026-01-06"),
+			now:  thursday,
+			want: Summary{CompletionsThisWeek: 2, BestStreak: 3},
+		},
+		{
+			name: "best streak is kept after a gap",
+			days: days("2025-12-01", "2025-12-02", "2025-12-03", "2025-12-04", "2026-01-07", "2026-01-08"),
+			now:  thursday,
+			want: Summary{CompletionsToday: 1, CompletionsThisWeek: 2, CurrentStreak: 2, BestStreak: 4},
+		},
+		{
+			name: "week starts on Monday",
+			days: days("2026-01-04", "2026-01-05"),
+			now:  time.Date(2026, 1, 5, 23, 59, 0, 0, time.Local), // Monday
+			want: Summary{CompletionsToday: 1, CompletionsThisWeek: 1, CurrentStreak: 2, BestStreak: 2},
+		},
+		{
+			name: "Sunday is the end of the week",
+			days: days("2026-01-05", "2026-01-07", "2026-01-11"),
+			now:  time.Date(2026, 1, 11, 0, 0, 0, 0, time.Local), // Sunday
+			want: Summary{CompletionsToday: 1, CompletionsThisWeek: 3, CurrentStreak: 1, BestStreak: 1},
+		},
+		{
+			name: "streak and week run over the new year",
+			days: days("2025-12-29", "2025-12-30", "2025-12-31", "2026-01-01"),
+			now:  time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local), // Thursday
+			want: Summary{CompletionsToday: 1, CompletionsThisWeek: 4, CurrentStreak: 4, BestStreak: 4},
+		},
+		{
+			name: "leap day",
+			days: days("2028-02-28", "2028-02-29", "2028-03-01"),
+			now:  time.Date(2028, 3, 2, 9, 0, 0, 0, time.Local),
+			want: Summary{CompletionsThisWeek: 3, CurrentStreak: 3, BestStreak: 3},
+		},
+		{
+			name: "clocks going forward",
+			days: days("2026-03-07", "2026-03-08", "2026-03-09"),
+			now:  time.Date(2026, 3, 9, 0, 30, 0, 0, newYork),
+			want: Summary{CompletionsToday: 1, CompletionsThisWeek: 1, CurrentStreak: 3, BestStreak: 3},
+		},
+		{
+			name: "yesterday was 25 hours long",
+			days: days("2026-10-31", "2026-11-01"),
+			now:  time.Date(2026, 11, 2, 0, 30, 0, 0, newYork),
+			want: Summary{CompletionsThisWeek: 0, CurrentStreak: 2, BestStreak: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(tt.days, tt.now); got != tt.want {
+				t.Errorf("Summarize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// summarizeByCalendar works out a summary the slow way, by walking the
+// calendar back from now, for checking Summarize against
+func summarizeByCalendar(counts map[string]int, now time.Time) Summary {
+	date := func(daysAgo int) string {
+		return now.AddDate(0, 0, -daysAgo).Format("2006-01-02")
+	}
+
+	var s Summary
+	s.CompletionsToday = counts[date(0)]
+	for ago := 0; ; ago++ {
+		s.CompletionsThisWeek += counts[date(ago)]
+		if now.AddDate(0, 0, -ago).Weekday() == time.Monday {
+			break
+		}
+	}
+
+	start := 0
+	if counts[date(0)] == 0 {
+		start = 1
+	}
+	for counts[date(start+s.CurrentStreak)] > 0 {
+		s.CurrentStreak++
+	}
+
+	var sorted []string
+	for d := range counts {
+		sorted = append(sorted, d)
+	}
+	slices.Sort(sorted)
+	for _, d := range sorted {
+		t, _ := time.Parse("2006-01-02", d)
+		run := 0
+		for counts[t.AddDate(0, 0, -run).Format("2006-01-02")] > 0 {
+			run++
+		}
+		s.BestStreak = max(s.BestStreak, run)
+	}
+	return s
+}
+
+// FuzzSummarize checks Summarize against a calendar walk for histories of
+// up to a year, each byte completing the habit that many days ago
+func FuzzSummarize(f *testing.F) {
+	f.Add([]byte{0, 1, 2, 3}, uint16(0), uint8(8))
+	f.Add([]byte{1, 1, 2, 5, 6, 7, 200}, uint16(3), uint8(23))
+	f.Add([]byte{2, 3, 4}, uint16(364), uint8(0))
+	f.Add([]byte{}, uint16(59), uint8(12))
+
+	f.Fuzz(func(t *testing.T, history []byte, dayOfYear uint16, hour uint8) {
+		now := time.Date(2026, 1, 1+int(dayOfYear%366), int(hour%24), 30, 0, 0, time.Local)
+
+		counts := make(map[string]int)
+		for _, ago := range history {
+			counts[now.AddDate(0, 0, -int(ago)).Format("2006-01-02")]++
+		}
+		var input []DayCount
+		for d, n := range counts {
+			input = append(input, DayCount{Date: d, Count: n})
+		}
+		slices.SortFunc(input, func(a, b DayCount) int { return strings.Compare(a.Date, b.Date) })
+
+		got := Summarize(input, now)
+		if want := summarizeByCalendar(counts, now); got != want {
+			t.Errorf("Summarize(%v, %v) = %+v, want %+v", input, now, got, want)
+		}
+	})
+}
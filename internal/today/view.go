@@ -1,32 +1,78 @@
 package today
 
 import (
+	"context"
 	"fmt"
-	"time"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/vittolewerissa/hbt/internal/checkin"
+	"github.com/vittolewerissa/hbt/internal/history"
+	"github.com/vittolewerissa/hbt/internal/shared/clock"
 	"github.com/vittolewerissa/hbt/internal/shared/db"
+	"github.com/vittolewerissa/hbt/internal/shared/model"
 	"github.com/vittolewerissa/hbt/internal/shared/ui"
 )
 
+// View modes
+type viewMode int
+
+const (
+	modeList viewMode = iota
+	modeNote
+	modeJournal
+	modeCheckIn
+)
+
 // Model is the today tab model
 type Model struct {
-	service *Service
-	habits  []HabitWithStatus
-	cursor  int
-	width   int
-	height  int
-	keys    ui.KeyMap
-	err     error
+	service        *Service
+	checkIns       *checkin.Service
+	loads          *ui.Loader
+	clock          clock.Clock
+	habits         []HabitWithStatus
+	visible        []int // indexes into habits that pass the filter
+	entries        []entry
+	collapsed      map[int64]bool // section IDs that are collapsed
+	sortMode       model.SortMode
+	filterBar      ui.FilterBar
+	filter         model.HabitFilter
+	list           ui.ScrollList
+	mode           viewMode
+	note           textarea.Model
+	noteHabit      HabitWithStatus
+	noteErr        error
+	journal        []JournalEntry
+	journalShown   []int // indexes into journal that match the search
+	journalSearch  ui.FilterBar
+	journalByHabit bool
+	journalList    ui.ScrollList
+	checkIn        checkInForm
+	todayCheckIn   *model.CheckIn
+	checkInOffered string // date the check-in was last offered
+	newDay         bool   // the day changed while open; shown until a key is pressed
+	width          int
+	height         int
+	panelHeight    int
+	panelWidth     int
+	keys           ui.KeyMap
+	err            error
 }
 
 // New creates a new today model
-func New(database *db.DB) Model {
+func New(database *db.DB, keys ui.KeyMap) Model {
+	search := ui.NewFilterBar()
+	search.SetPlaceholder("note or habit")
 	return Model{
-		service: NewService(database),
-		keys:    ui.DefaultKeyMap,
+		service:       NewService(database),
+		checkIns:      checkin.NewService(database),
+		loads:         &ui.Loader{},
+		clock:         database.Clock,
+		filterBar:     ui.NewFilterBar(),
+		journalSearch: search,
+		keys:          keys,
 	}
 }
 
@@ -37,31 +83,91 @@ func (m Model) Init() tea.Cmd {
 
 // TodayLoadedMsg is sent when today's data is loaded
 type TodayLoadedMsg struct {
-	Habits []HabitWithStatus
-	Err    error
+	Habits     []HabitWithStatus
+	Collapsed  map[int64]bool
+	SortMode   model.SortMode
+	CheckIn    *model.CheckIn
+	CheckInDue bool
+	Err        error
 }
 
 // CompletionToggledMsg is sent when a completion is toggled
 type CompletionToggledMsg struct {
 	HabitID   int64
 	Completed bool
+	Change    *history.Change
 	Err       error
 }
 
 func (m Model) loadData() tea.Msg {
-	habits, err := m.service.GetHabitsForToday()
-	return TodayLoadedMsg{Habits: habits, Err: err}
+	ctx, cancel := m.loads.Start()
+	defer cancel()
+	habits, err := m.service.GetHabitsForToday(ctx)
+	if err != nil {
+		return TodayLoadedMsg{Err: err}
+	}
+	collapsed, err := m.service.CollapsedSections(ctx)
+	if err != nil {
+		return TodayLoadedMsg{Err: err}
+	}
+	mode, err := m.service.SortMode(ctx)
+	if err != nil {
+		return TodayLoadedMsg{Err: err}
+	}
+	checkIn, err := m.checkIns.Today(ctx)
+	if err != nil {
+		return TodayLoadedMsg{Err: err}
+	}
+	due, err := m.checkIns.Due(ctx)
+	return TodayLoadedMsg{
+		Habits:     habits,
+		Collapsed:  collapsed,
+		SortMode:   mode,
+		CheckIn:    checkIn,
+		CheckInDue: due,
+		Err:        err,
+	}
 }
 
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case TodayLoadedMsg:
+		if ui.Superseded(msg.Err) {
+			return m, nil
+		}
 		if msg.Err != nil {
 			m.err = msg.Err
 			return m, nil
 		}
+		// A load that works clears the error of one that timed out
+		m.err = nil
 		m.habits = msg.Habits
+		m.sortMode = msg.SortMode
+		if m.collapsed == nil {
+			m.collapsed = msg.Collapsed
+		}
+		m.todayCheckIn = msg.CheckIn
+		m.applyFilter()
+
+		// Offer the check-in once a day, without interrupting anything else
+		if msg.CheckInDue && m.mode == modeList && !m.filterBar.Editing() &&
+			m.checkInOffered != m.clock.Now().Format("2006-01-02") {
+			return m, m.openCheckIn()
+		}
+		return m, nil
+
+	case CheckInSavedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		return m, m.loadData
+
+	case SectionsSavedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+		}
 		return m, nil
 
 	case CompletionToggledMsg:
@@ -72,48 +178,217 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		// Reload data to refresh streaks
 		return m, m.loadData
 
+	case NoteSavedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		return m, m.loadData
+
+	case NoteEditedMsg:
+		return m.handleEdited(msg), nil
+
+	case JournalLoadedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.journal = msg.Entries
+		m.applyJournalSearch()
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 
 	case tea.KeyMsg:
 		return m.handleKey(msg)
+
+	case ui.ScrollMsg:
+		switch m.mode {
+		case modeList:
+			m.list.Move(msg.Delta, len(m.entries))
+			m.syncScroll()
+		case modeJournal:
+			m.journalList.Move(msg.Delta, len(m.journalShown))
+			m.syncJournalScroll()
+		}
+
+	case ui.ClickMsg:
+		if m.mode != modeList {
+			return m, nil
+		}
+		// Clicking a row selects it and toggles the habit or section
+		line := msg.Row - lipgloss.Height(m.renderHeader()) + 1
+		index := m.list.RowAt(line, len(m.entries))
+		if m.err == nil && index >= 0 && index < len(m.entries) {
+			m.list.Cursor = index
+			m.syncScroll()
+			return m, m.activate()
+		}
 	}
 
 	return m, nil
 }
 
 func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.newDay {
+		m.newDay = false
+		m.syncScroll()
+	}
+
+	switch m.mode {
+	case modeNote:
+		return m.handleNoteKey(msg)
+	case modeJournal:
+		return m.handleJournalKey(msg)
+	case modeCheckIn:
+		return m.handleCheckInKey(msg)
+	}
+
+	if m.filterBar.Editing() {
+		changed, cmd := m.filterBar.Update(msg, m.keys)
+		if changed {
+			m.list.Cursor = 0
+		}
+		m.applyFilter()
+		return m, cmd
+	}
+
+	if m.list.HandleKey(msg, m.keys, len(m.entries)) {
+		m.syncScroll()
+		return m, nil
+	}
+
 	switch {
-	case key.Matches(msg, m.keys.Up):
-		if m.cursor > 0 {
-			m.cursor--
+	case key.Matches(msg, m.keys.Filter):
+		cmd := m.filterBar.Open()
+		m.syncScroll()
+		return m, cmd
+	case key.Matches(msg, m.keys.Back):
+		if m.filterBar.Query() != "" {
+			m.SetFilter("")
 		}
-	case key.Matches(msg, m.keys.Down):
-		if m.cursor < len(m.habits)-1 {
-			m.cursor++
+	case key.Matches(msg, m.keys.Collapse):
+		if e, ok := m.selected(); ok && m.sortMode == model.SortCategory {
+			return m, m.toggleSection(e.section)
 		}
 	case key.Matches(msg, m.keys.Toggle), key.Matches(msg, m.keys.Select):
-		if len(m.habits) > 0 {
-			habit := m.habits[m.cursor]
-			return m, m.toggleCompletion(habit.ID)
-		}
+		return m, m.activate()
+	case key.Matches(msg, m.keys.Note):
+		return m, m.openNote()
+	case key.Matches(msg, m.keys.Journal):
+		return m, m.openJournal()
+	case key.Matches(msg, m.keys.CheckIn):
+		return m, m.openCheckIn()
 	}
 
 	return m, nil
 }
 
-func (m Model) toggleCompletion(habitID int64) tea.Cmd {
+// activate toggles the habit under the cursor, or collapses/expands the
+// section when the cursor is on its header
+func (m *Model) activate() tea.Cmd {
+	e, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	if e.habit == -1 {
+		return m.toggleSection(e.section)
+	}
+	return m.toggleCompletion(m.habits[e.habit])
+}
+
+// NewDay shows the new day banner and forgets what only applied to the day
+// that ended. The caller reloads the data.
+func (m *Model) NewDay() {
+	m.newDay = true
+	m.checkInOffered = ""
+	m.syncScroll()
+}
+
+// FilterQuery returns the active filter query
+func (m Model) FilterQuery() string {
+	return m.filterBar.Query()
+}
+
+// SetFilter replaces the filter query, e.g. when carrying it over from the habits tab
+func (m *Model) SetFilter(query string) {
+	if query != m.filterBar.Query() {
+		m.filterBar.SetQuery(query)
+		m.list.Cursor = 0
+	}
+	m.applyFilter()
+}
+
+// applyFilter recomputes which habits are shown
+func (m *Model) applyFilter() {
+	m.filter = model.ParseHabitFilter(m.filterBar.Query())
+	m.visible = m.visible[:0]
+	for i := range m.habits {
+		h := &m.habits[i]
+		if m.filter.Matches(&h.Habit, h.IsDue, h.CompletedToday) {
+			m.visible = append(m.visible, i)
+		}
+	}
+	m.buildEntries()
+	m.list.Clamp(len(m.entries))
+	m.syncScroll()
+}
+
+// SetHeight sets the number of rows available for the tab's panel content
+func (m *Model) SetHeight(height int) {
+	m.panelHeight = height
+	m.syncScroll()
+	m.syncJournalScroll()
+}
+
+// SetWidth sets the number of columns available for the tab's panel content
+func (m *Model) SetWidth(width int) {
+	m.panelWidth = width
+	if m.mode == modeNote {
+		m.note.SetWidth(m.noteWidth())
+	}
+}
+
+// syncScroll sizes the list to the space left under the header and keeps
+// the cursor in view
+func (m *Model) syncScroll() {
+	m.list.Height = 0
+	if m.panelHeight > 0 {
+		m.list.Height = max(1, m.panelHeight-lipgloss.Height(m.renderHeader())+1)
+	}
+	m.list.Follow(m.list.Cursor, m.list.Cursor, len(m.entries))
+}
+
+func (m Model) toggleCompletion(habit HabitWithStatus) tea.Cmd {
 	return func() tea.Msg {
-		completed, err := m.service.ToggleCompletion(habitID)
-		return CompletionToggledMsg{HabitID: habitID, Completed: completed, Err: err}
+		ctx, cancel := db.WithTimeout(context.Background())
+		defer cancel()
+		t, err := m.service.ToggleCompletion(ctx, habit.ID)
+		if err != nil {
+			return CompletionToggledMsg{HabitID: habit.ID, Err: err}
+		}
+		label := fmt.Sprintf("Completed '%s'", habit.Name)
+		if !t.Completed {
+			label = fmt.Sprintf("Uncompleted '%s'", habit.Name)
+		}
+		return CompletionToggledMsg{
+			HabitID:   habit.ID,
+			Completed: t.Completed,
+			Change: &history.Change{
+				Label: label,
+				Undo:  func(ctx context.Context) error { return m.service.UndoToggle(ctx, t) },
+				Redo:  func(ctx context.Context) error { return m.service.RedoToggle(ctx, t) },
+			},
+		}
 	}
 }
 
 // View renders the today tab (with title)
 func (m Model) View() string {
 	var s string
-	date := time.Now().Format("Monday, January 2")
+	date := m.clock.Now().Format("Monday, January 2")
 	s += ui.Title.Render("Today - " + date) + "\n\n"
 	s += m.ViewContent()
 	return s
@@ -122,17 +397,60 @@ func (m Model) View() string {
 // ViewContent renders just the content without title (for titled panels)
 func (m Model) ViewContent() string {
 	if m.err != nil {
-		return ui.MutedText.Render(fmt.Sprintf("Error: %v", m.err))
+		return ui.MutedText.Render(fmt.Sprintf("Error: %v", db.Explain(m.err)))
 	}
 
+	switch m.mode {
+	case modeNote:
+		return m.renderNoteContent()
+	case modeJournal:
+		return m.renderJournalContent()
+	case modeCheckIn:
+		return m.renderCheckInContent()
+	}
+
+	s := m.renderHeader()
+
+	if len(m.habits) == 0 {
+		s += ui.MutedText.Render("No habits yet. Switch to the Habits tab to add some.")
+		return s
+	}
+
+	if len(m.visible) == 0 {
+		s += ui.MutedText.Render("No habits match the filter.")
+		return s
+	}
+
+	// Habit list, grouped into sections
+	var rows []string
+	for i, e := range m.entries {
+		if e.habit == -1 {
+			rows = append(rows, m.renderSectionHeader(i, e))
+		} else {
+			rows = append(rows, m.renderHabit(i, m.habits[e.habit]))
+		}
+	}
+	s += m.list.Render(rows)
+
+	return s
+}
+
+// renderHeader renders the date and progress lines shown above the habit list
+func (m Model) renderHeader() string {
 	var s string
 
+	if m.newDay {
+		s += ui.MutedText.Render("☀ A new day has started") + "\n"
+	}
+
 	// Date subtitle
-	date := time.Now().Format("Monday, January 2")
+	date := m.clock.Now().Format("Monday, January 2")
+	if summary := m.renderCheckInSummary(); summary != "" {
+		date += " · " + summary
+	}
 	s += ui.MutedText.Render(date) + "\n\n"
 
 	if len(m.habits) == 0 {
-		s += ui.MutedText.Render("No habits yet. Switch to the Habits tab to add some.")
 		return s
 	}
 
@@ -153,17 +471,14 @@ func (m Model) ViewContent() string {
 		s += ui.Subtitle.Render(progress) + "\n"
 	}
 
-	// Habit list
-	for i, habit := range m.habits {
-		s += m.renderHabit(i, habit) + "\n"
-	}
+	s += m.filterBar.View(m.filter.Chips(), len(m.visible), len(m.habits))
 
 	return s
 }
 
 func (m Model) renderHabit(index int, habit HabitWithStatus) string {
 	cursor := "  "
-	if index == m.cursor {
+	if index == m.list.Cursor {
 		cursor = "> "
 	}
 
@@ -182,8 +497,12 @@ func (m Model) renderHabit(index int, habit HabitWithStatus) string {
 		checkStyle = ui.CheckboxChecked
 		nameStyle = ui.CompletedItem
 	} else {
+		// Open checkboxes take the category's color
 		checkStyle = ui.Checkbox
-		if index == m.cursor {
+		if habit.Category != nil {
+			checkStyle = ui.CategoryStyle(checkStyle, habit.Category.Color)
+		}
+		if index == m.list.Cursor {
 			nameStyle = ui.SelectedItem
 		} else {
 			nameStyle = ui.NormalItem
@@ -199,8 +518,13 @@ func (m Model) renderHabit(index int, habit HabitWithStatus) string {
 		line += ui.StreakBadge.Render(streak)
 	}
 
-	// Add category emoji (optional)
-	if habit.Category != nil && habit.Category.Emoji != "" {
+	// Mark habits with a note today
+	if habit.NoteToday != "" {
+		line += " " + ui.MutedText.Render("✎")
+	}
+
+	// Sections show the category, so only flat lists need its emoji
+	if m.sortMode != model.SortCategory && habit.Category != nil && habit.Category.Emoji != "" {
 		line += " " + habit.Category.Emoji
 	}
 
@@ -225,5 +549,25 @@ func (m Model) renderHabit(index int, habit HabitWithStatus) string {
 
 // Focused returns whether this view should receive key events
 func (m Model) Focused() bool {
-	return false
+	return m.filterBar.Editing() || m.mode == modeNote || m.mode == modeCheckIn || m.journalSearch.Editing()
+}
+
+// HelpSections returns the key bindings relevant to the today tab
+func (m Model) HelpSections() []ui.HelpSection {
+	switch m.mode {
+	case modeNote:
+		return m.noteHelpSections()
+	case modeJournal:
+		return m.journalHelpSections()
+	case modeCheckIn:
+		return m.checkInHelpSections()
+	}
+	if m.filterBar.Editing() {
+		return []ui.HelpSection{{Title: "Filter", Bindings: ui.FilterHelp(m.keys)}}
+	}
+	return []ui.HelpSection{
+		{Title: "Today", Bindings: []key.Binding{
+			m.keys.Up, m.keys.Down, m.keys.Toggle, m.keys.Note, m.keys.Collapse, m.keys.Filter, m.keys.Journal, m.keys.CheckIn,
+		}},
+	}
 }